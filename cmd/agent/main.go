@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,9 +13,13 @@ import (
 
 	"github.com/yogenyslav/ya-metrics/internal/agent"
 	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
 	buildinfo "github.com/yogenyslav/ya-metrics/pkg/build_info"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 	"github.com/yogenyslav/ya-metrics/pkg/secure"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 // To set build info, use the following ldflags:
@@ -45,7 +50,41 @@ func run() error {
 
 	l := zerolog.New(os.Stdout).With().Timestamp().Logger()
 
-	a := agent.New(http.DefaultClient, cfg, sg, &l)
+	client := http.DefaultClient
+	var tlsCfg *tls.Config
+	if cfg.TLS() {
+		tlsCfg, err = secure.ClientTLSConfig(cfg.TLSCAFile, cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return errs.Wrap(err, "create TLS config")
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsCfg
+		client = &http.Client{Transport: transport}
+	}
+
+	a := agent.New(client, cfg, sg, &l)
+	if cfg.CryptoKey != "" {
+		key, err := secure.LoadPublicKey(cfg.CryptoKey)
+		if err != nil {
+			return errs.Wrap(err, "load crypto key")
+		}
+		a.UseEncryption(secure.NewEncryptor(key))
+	}
+	if cfg.Transport == config.TransportGRPC {
+		creds := insecure.NewCredentials()
+		if tlsCfg != nil {
+			creds = credentials.NewTLS(tlsCfg)
+		}
+
+		conn, err := grpc.NewClient(cfg.GRPCAddr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return errs.Wrap(err, "create gRPC client")
+		}
+		defer conn.Close()
+
+		a.UseGRPC(pb.NewMetricsClient(conn))
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -61,7 +100,9 @@ func run() error {
 	signal.Stop(stop)
 
 	cancel()
-	a.Shutdown()
+	if err := a.Shutdown(); err != nil {
+		return errs.Wrap(err, "shutdown agent")
+	}
 
 	return nil
 }
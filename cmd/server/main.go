@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -14,6 +15,9 @@ import (
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 )
 
+// shutdownTimeout limits waiting for in-flight requests on shutdown.
+const shutdownTimeout = 30 * time.Second
+
 // To set build info, use the following ldflags:
 // -ldflags "-X main.buildVersion=$(VERSION) -X main.buildDate=$(DATE) -X main.buildCommit=$(COMMIT)".
 var (
@@ -53,14 +57,19 @@ func run() error {
 	if err != nil {
 		return errs.Wrap(err, "start server")
 	}
-	defer srv.Shutdown()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 	<-stop
 	signal.Stop(stop)
 
-	cancel()
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return errs.Wrap(err, "shutdown server")
+	}
+	log.Info().Msg("server shutdown gracefully")
 
 	return nil
 }
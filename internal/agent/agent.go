@@ -11,9 +11,15 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/yogenyslav/ya-metrics/internal/agent/collector"
 	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"google.golang.org/grpc"
 )
 
-const gracefulShutdownTimeout = 60 * time.Second
+const (
+	gracefulShutdownTimeout = 60 * time.Second
+	finalSendTimeout        = 10 * time.Second
+)
 
 // ErrUpdateMetric indicates a failure to update a metric.
 var ErrUpdateMetric = errors.New("failed to update metric")
@@ -23,6 +29,20 @@ type Client interface {
 	Do(r *http.Request) (*http.Response, error)
 }
 
+// GRPCClient is an interface that defines the gRPC method used to send metrics.
+type GRPCClient interface {
+	UpdateBatch(
+		ctx context.Context,
+		in *pb.UpdateBatchRequest,
+		opts ...grpc.CallOption,
+	) (*pb.UpdateBatchResponse, error)
+}
+
+// Encryptor is an interface for encrypting request bodies.
+type Encryptor interface {
+	Encrypt(data []byte) ([]byte, error)
+}
+
 // SignatureGenerator is an interface for generating hash signatures.
 type SignatureGenerator interface {
 	SignatureSHA256(data []byte) string
@@ -31,6 +51,10 @@ type SignatureGenerator interface {
 // Agent struct to collect and send metrics to server.
 type Agent struct {
 	client   Client
+	grpc     GRPCClient
+	enc      Encryptor
+	coll     *collector.Collector
+	realIP   string
 	cfg      *config.Config
 	sg       SignatureGenerator
 	l        *zerolog.Logger
@@ -48,10 +72,32 @@ func New(client Client, cfg *config.Config, sg SignatureGenerator, l *zerolog.Lo
 	}
 }
 
+// UseGRPC makes the agent send metrics with the gRPC client instead of HTTP.
+func (a *Agent) UseGRPC(client GRPCClient) {
+	a.grpc = client
+}
+
+// UseEncryption makes the agent encrypt HTTP request bodies.
+func (a *Agent) UseEncryption(enc Encryptor) {
+	a.enc = enc
+}
+
 // Start begins the metric collection and reporting process.
 func (a *Agent) Start(ctx context.Context) error {
-	coll := collector.NewCollector(a.cfg.PollIntervalSec, a.l)
+	realIP, err := outboundIP(a.serverAddr())
+	if err != nil {
+		a.l.Warn().Err(err).Msg("failed to detect outbound address, server may reject metrics")
+	}
+	a.realIP = realIP
+
+	sources, err := collector.NewSources(a.cfg)
+	if err != nil {
+		return errs.Wrap(err, "create metric sources")
+	}
+
+	coll := collector.NewCollector(a.cfg.PollIntervalSec, a.l, sources...)
 	coll.Collect(ctx)
+	a.coll = coll
 
 	go func() {
 		defer func() {
@@ -76,12 +122,30 @@ func (a *Agent) Start(ctx context.Context) error {
 	return nil
 }
 
-// Shutdown performs graceful shutdown for agent.
-func (a *Agent) Shutdown() {
+// Shutdown waits for the reporting loop to stop, then collects and sends metrics for the last time
+// within finalSendTimeout, so nothing collected since the last report is lost.
+//
+// The returned error tells whether the final send failed.
+func (a *Agent) Shutdown() error {
 	select {
 	case <-a.shutdown:
-		log.Info().Msg("agent shutdown gracefully")
+		log.Info().Msg("agent stopped reporting")
 	case <-time.After(gracefulShutdownTimeout):
 		log.Warn().Msg("graceful shutdown timeout reached, exiting")
+		return errors.New("graceful shutdown timeout reached")
+	}
+
+	if a.coll == nil {
+		return nil
 	}
+	a.coll.Poll()
+
+	ctx, cancel := context.WithTimeout(context.Background(), finalSendTimeout)
+	defer cancel()
+
+	if err := a.sendAllMetrics(ctx, a.coll); err != nil {
+		return errs.Wrap(err, "send final metrics")
+	}
+	log.Info().Msg("agent sent final metrics and shutdown gracefully")
+	return nil
 }
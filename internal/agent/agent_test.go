@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestAgent_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    bool
+	}{
+		{
+			name:       "Final metrics are sent",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Final send failure is reported",
+			statusCode: http.StatusBadRequest,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := new(mocks.HTTPClient)
+			client.On("Do", mock.Anything).Return(&http.Response{
+				StatusCode: tt.statusCode,
+				Body:       http.NoBody,
+			}, nil)
+
+			a := New(client, &config.Config{
+				ServerAddr:        "http://127.0.0.1:8080",
+				PollIntervalSec:   3600,
+				ReportIntervalSec: 3600,
+				RateLimit:         1,
+				BatchSize:         100,
+				Collectors:        []string{"general", "memory"},
+			}, nil, zerolog.Ctx(context.Background()))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			require.NoError(t, a.Start(ctx))
+			cancel()
+
+			err := a.Shutdown()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			client.AssertNumberOfCalls(t, "Do", 1)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"time"
 
@@ -11,35 +12,28 @@ import (
 
 // Collector struct to collect metrics.
 type Collector struct {
-	PollInterval       int
-	memoryMetrics      *MemoryMetrics
-	generalMetrics     *GeneralMetrics
-	utilizationMetrics *UtilizationMetrics
-	l                  *zerolog.Logger
-	updaters           []func() error
-	mu                 *sync.Mutex
-	wg                 *sync.WaitGroup
-}
-
-// NewCollector creates a new Collector instance.
-func NewCollector(pollInterval int, l *zerolog.Logger) *Collector {
-	c := &Collector{
-		PollInterval:       pollInterval,
-		memoryMetrics:      NewMemoryMetrics(),
-		generalMetrics:     NewGeneralMetrics(),
-		utilizationMetrics: NewUtilizationMetrics(),
-		l:                  l,
-		mu:                 &sync.Mutex{},
-		wg:                 &sync.WaitGroup{},
-	}
+	PollInterval int
+	sources      []Source
+	gauges       *seriesSet[float64]
+	counters     *seriesSet[int64]
+	l            *zerolog.Logger
+	mu           *sync.Mutex
+	pollMu       *sync.Mutex
+	wg           *sync.WaitGroup
+}
 
-	c.updaters = []func() error{
-		c.updateMemoryMetrics,
-		c.updateGeneralMetrics,
-		c.updateUtilizationMetrics,
+// NewCollector creates a new Collector instance polling the sources.
+func NewCollector(pollInterval int, l *zerolog.Logger, sources ...Source) *Collector {
+	return &Collector{
+		PollInterval: pollInterval,
+		sources:      sources,
+		gauges:       newSeriesSet[float64](model.NewGaugeMetric),
+		counters:     newSeriesSet[int64](model.NewCounterMetric),
+		l:            l,
+		mu:           &sync.Mutex{},
+		pollMu:       &sync.Mutex{},
+		wg:           &sync.WaitGroup{},
 	}
-
-	return c
 }
 
 // Collect starts collecting metrics at specified intervals.
@@ -53,37 +47,49 @@ func (c *Collector) Collect(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				c.updateMetrics()
+				c.updateMetrics(ctx)
 			}
 		}
 	}()
 }
 
-func (c *Collector) updateMetrics() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// Poll collects metrics once.
+func (c *Collector) Poll() {
+	c.updateMetrics(context.Background())
+}
+
+// updateMetrics polls all sources, polls never overlap so sources may keep state between them.
+func (c *Collector) updateMetrics(ctx context.Context) {
+	c.pollMu.Lock()
+	defer c.pollMu.Unlock()
 
-	errCh := make(chan error, len(c.updaters))
+	batches := make([]*batch, len(c.sources))
+	errList := make([]error, len(c.sources))
 
-	c.wg.Add(len(c.updaters))
-	for _, updater := range c.updaters {
-		go func(u func() error) {
+	c.wg.Add(len(c.sources))
+	for i, source := range c.sources {
+		go func() {
 			defer c.wg.Done()
-			err := u()
-			errCh <- err
-			if err == nil {
-				c.l.Info().Msg("updated metrics")
-			}
-		}(updater)
+			batches[i] = &batch{}
+			errList[i] = source.Collect(ctx, batches[i])
+		}()
 	}
 	c.wg.Wait()
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	success := true
-	for range c.updaters {
-		if err := <-errCh; err != nil {
+	for i, source := range c.sources {
+		if err := errList[i]; err != nil {
 			success = false
-			c.l.Error().Err(err).Msg("failed to update metrics")
+			c.l.Error().Err(err).Str("source", source.Name()).Msg("failed to update metrics")
+			continue
 		}
+
+		c.gauges.merge(source.Name(), batches[i].gauges, func(_, value float64) float64 { return value })
+		c.counters.merge(source.Name(), batches[i].counters, func(prev, delta int64) int64 { return prev + delta })
+		c.l.Info().Str("source", source.Name()).Msg("updated metrics")
 	}
 
 	if success {
@@ -91,63 +97,14 @@ func (c *Collector) updateMetrics() {
 	}
 }
 
-// MemoryMetrics returns the current memory metrics.
-func (c *Collector) MemoryMetrics() *MemoryMetrics {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.memoryMetrics
-}
-
-// GeneralMetrics returns the current general metrics.
-func (c *Collector) GeneralMetrics() *GeneralMetrics {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.generalMetrics
-}
-
 // GetAllGaugeMetrics returns all gauge metrics collected by the Collector.
 func (c *Collector) GetAllGaugeMetrics() []*model.Metrics[float64] {
-	m := []*model.Metrics[float64]{
-		c.generalMetrics.RandomValue,
-		c.memoryMetrics.Alloc,
-		c.memoryMetrics.BuckHashSys,
-		c.memoryMetrics.Frees,
-		c.memoryMetrics.GCCPUFraction,
-		c.memoryMetrics.GCSys,
-		c.memoryMetrics.HeapAlloc,
-		c.memoryMetrics.HeapIdle,
-		c.memoryMetrics.HeapInuse,
-		c.memoryMetrics.HeapObjects,
-		c.memoryMetrics.HeapReleased,
-		c.memoryMetrics.HeapSys,
-		c.memoryMetrics.LastGC,
-		c.memoryMetrics.Lookups,
-		c.memoryMetrics.MCacheInuse,
-		c.memoryMetrics.MCacheSys,
-		c.memoryMetrics.MSpanInuse,
-		c.memoryMetrics.MSpanSys,
-		c.memoryMetrics.Mallocs,
-		c.memoryMetrics.NextGC,
-		c.memoryMetrics.NumForcedGC,
-		c.memoryMetrics.NumGC,
-		c.memoryMetrics.OtherSys,
-		c.memoryMetrics.PauseTotalNs,
-		c.memoryMetrics.StackInuse,
-		c.memoryMetrics.StackSys,
-		c.memoryMetrics.Sys,
-		c.memoryMetrics.TotalAlloc,
-		c.utilizationMetrics.TotalMemory,
-		c.utilizationMetrics.FreeMemory,
-	}
-	m = append(m, c.utilizationMetrics.CPUUtilization...)
-	return m
+	return c.gauges.metrics()
 }
 
 // GetAllCounterMetrics returns all counter metrics collected by the Collector.
 func (c *Collector) GetAllCounterMetrics() []*model.Metrics[int64] {
-	return []*model.Metrics[int64]{
-		c.generalMetrics.PollCount,
-	}
+	return c.counters.metrics()
 }
 
 // GetAllMetrics returns all metrics collected by the Collector.
@@ -170,3 +127,96 @@ func (c *Collector) GetAllMetrics() []*model.MetricsDto {
 
 	return metrics
 }
+
+// Reported subtracts deltas of the reported counters from the collected ones,
+// so that the next report carries only increments made since.
+// A counter series dropped by its source loses the increments not reported yet.
+func (c *Collector) Reported(metrics []*model.MetricsDto) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, m := range metrics {
+		if m.Type != model.Counter || m.Delta == nil {
+			continue
+		}
+		if sr, ok := c.counters.series[model.SeriesKey(m.ID, m.Labels)]; ok {
+			sr.metric.Value -= *m.Delta
+		}
+	}
+}
+
+type sample[T int64 | float64] struct {
+	id     string
+	value  T
+	labels model.Labels
+}
+
+// batch is a Sink buffering metrics of a single source until they are merged into the collector.
+type batch struct {
+	gauges   []sample[float64]
+	counters []sample[int64]
+}
+
+// Gauge implements Sink.
+func (b *batch) Gauge(id string, value float64, labels model.Labels) {
+	b.gauges = append(b.gauges, sample[float64]{id: id, value: value, labels: labels})
+}
+
+// Counter implements Sink.
+func (b *batch) Counter(id string, delta int64, labels model.Labels) {
+	b.counters = append(b.counters, sample[int64]{id: id, value: delta, labels: labels})
+}
+
+type series[T int64 | float64] struct {
+	metric *model.Metrics[T]
+	source string
+}
+
+// seriesSet keeps series in the order they first appeared.
+type seriesSet[T int64 | float64] struct {
+	keys      []string
+	series    map[string]*series[T]
+	newMetric func(id string) *model.Metrics[T]
+}
+
+func newSeriesSet[T int64 | float64](newMetric func(id string) *model.Metrics[T]) *seriesSet[T] {
+	return &seriesSet[T]{
+		series:    make(map[string]*series[T]),
+		newMetric: newMetric,
+	}
+}
+
+// merge applies samples reported by the source and drops its series missing from them.
+func (s *seriesSet[T]) merge(source string, samples []sample[T], apply func(prev, value T) T) {
+	seen := make(map[string]struct{}, len(samples))
+	for _, smp := range samples {
+		key := model.SeriesKey(smp.id, smp.labels)
+		seen[key] = struct{}{}
+
+		sr, ok := s.series[key]
+		if !ok {
+			metric := s.newMetric(smp.id)
+			metric.Labels = smp.labels.Clone()
+			sr = &series[T]{metric: metric, source: source}
+			s.series[key] = sr
+			s.keys = append(s.keys, key)
+		}
+		sr.metric.Value = apply(sr.metric.Value, smp.value)
+	}
+
+	s.keys = slices.DeleteFunc(s.keys, func(key string) bool {
+		if _, ok := seen[key]; ok || s.series[key].source != source {
+			return false
+		}
+		delete(s.series, key)
+		return true
+	})
+}
+
+func (s *seriesSet[T]) metrics() []*model.Metrics[T] {
+	metrics := make([]*model.Metrics[T], 0, len(s.keys))
+	for _, key := range s.keys {
+		metrics = append(metrics, s.series[key].metric)
+	}
+	return metrics
+}
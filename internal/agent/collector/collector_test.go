@@ -2,13 +2,45 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
 )
 
+func defaultSources(t *testing.T) []Source {
+	t.Helper()
+
+	sources, err := NewSources(&config.Config{Collectors: []string{"general", "memory", "utilization"}})
+	require.NoError(t, err)
+	return sources
+}
+
+func gaugeValue(c *Collector, id string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.gauges.series[id]; ok {
+		return s.metric.Value
+	}
+	return 0
+}
+
+func counterValue(c *Collector, id string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if s, ok := c.counters.series[id]; ok {
+		return s.metric.Value
+	}
+	return 0
+}
+
 func TestCollector_Collect(t *testing.T) {
 	t.Parallel()
 
@@ -17,10 +49,10 @@ func TestCollector_Collect(t *testing.T) {
 			t.Parallel()
 
 			pollInterval := 1
-			c := NewCollector(pollInterval, zerolog.Ctx(context.Background()))
+			c := NewCollector(pollInterval, zerolog.Ctx(context.Background()), defaultSources(t)...)
 
-			initialPollCount := c.GeneralMetrics().PollCount.Value
-			initialAlloc := c.MemoryMetrics().Alloc.Value
+			initialPollCount := counterValue(c, "PollCount")
+			initialAlloc := gaugeValue(c, "Alloc")
 
 			ctx, cancel := context.WithCancel(context.Background())
 			c.Collect(ctx)
@@ -28,8 +60,8 @@ func TestCollector_Collect(t *testing.T) {
 			<-time.After(time.Second * time.Duration(pollInterval+1))
 			cancel()
 
-			assert.Greater(t, c.GeneralMetrics().PollCount.Value, initialPollCount)
-			assert.Greater(t, c.MemoryMetrics().Alloc.Value, initialAlloc)
+			assert.Greater(t, counterValue(c, "PollCount"), initialPollCount)
+			assert.Greater(t, gaugeValue(c, "Alloc"), initialAlloc)
 		},
 	)
 
@@ -38,10 +70,10 @@ func TestCollector_Collect(t *testing.T) {
 			t.Parallel()
 
 			pollInterval := 1
-			c := NewCollector(pollInterval, zerolog.DefaultContextLogger)
-			initialPollCount := c.GeneralMetrics().PollCount.Value
-			initialRandomValue := c.GeneralMetrics().RandomValue.Value
-			initialAlloc := c.MemoryMetrics().Alloc.Value
+			c := NewCollector(pollInterval, zerolog.DefaultContextLogger, defaultSources(t)...)
+			initialPollCount := counterValue(c, "PollCount")
+			initialRandomValue := gaugeValue(c, "RandomValue")
+			initialAlloc := gaugeValue(c, "Alloc")
 
 			ctx, cancel := context.WithCancel(context.Background())
 			c.Collect(ctx)
@@ -49,9 +81,9 @@ func TestCollector_Collect(t *testing.T) {
 			<-time.After(time.Second * time.Duration(pollInterval/2))
 			cancel()
 
-			assert.Equal(t, initialPollCount, c.GeneralMetrics().PollCount.Value)
-			assert.Equal(t, initialRandomValue, c.GeneralMetrics().RandomValue.Value)
-			assert.Equal(t, initialAlloc, c.MemoryMetrics().Alloc.Value)
+			assert.Equal(t, initialPollCount, counterValue(c, "PollCount"))
+			assert.Equal(t, initialRandomValue, gaugeValue(c, "RandomValue"))
+			assert.Equal(t, initialAlloc, gaugeValue(c, "Alloc"))
 		},
 	)
 }
@@ -59,9 +91,98 @@ func TestCollector_Collect(t *testing.T) {
 func TestCollector_GetAllMetrics(t *testing.T) {
 	t.Parallel()
 
-	col := NewCollector(1, nil)
+	nop := zerolog.Nop()
+	col := NewCollector(1, &nop, defaultSources(t)...)
+	col.Poll()
+
 	gaugeMetrics := col.GetAllGaugeMetrics()
 	counterMetrics := col.GetAllCounterMetrics()
 	allMetrics := col.GetAllMetrics()
+	assert.NotEmpty(t, gaugeMetrics)
+	assert.NotEmpty(t, counterMetrics)
 	assert.Len(t, allMetrics, len(gaugeMetrics)+len(counterMetrics))
 }
+
+type stubSource struct {
+	name  string
+	polls [][]string
+	err   error
+}
+
+func (s *stubSource) Name() string {
+	return s.name
+}
+
+func (s *stubSource) Collect(_ context.Context, sink Sink) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	ids := s.polls[0]
+	s.polls = s.polls[1:]
+	for _, id := range ids {
+		sink.Gauge("Value", 1, model.Labels{"id": id})
+		sink.Counter("Count", 2, model.Labels{"id": id})
+	}
+	return nil
+}
+
+func TestCollector_Poll(t *testing.T) {
+	t.Parallel()
+
+	nop := zerolog.Nop()
+	source := &stubSource{name: "stub", polls: [][]string{{"a", "b"}, {"b", "c"}}}
+	failing := &stubSource{name: "failing", err: errors.New("err")}
+	c := NewCollector(1, &nop, source, failing)
+
+	c.Poll()
+	assert.Len(t, c.GetAllGaugeMetrics(), 2)
+	assert.Equal(t, int64(2), counterValue(c, model.SeriesKey("Count", model.Labels{"id": "b"})))
+
+	c.Poll()
+	assert.Zero(t, gaugeValue(c, model.SeriesKey("Value", model.Labels{"id": "a"})))
+	assert.Equal(t, 1.0, gaugeValue(c, model.SeriesKey("Value", model.Labels{"id": "c"})))
+	assert.Equal(t, int64(4), counterValue(c, model.SeriesKey("Count", model.Labels{"id": "b"})))
+
+	var ids []string
+	for _, m := range c.GetAllCounterMetrics() {
+		ids = append(ids, m.Labels["id"])
+	}
+	assert.Equal(t, []string{"b", "c"}, ids)
+}
+
+// deliver imitates a successful report of the metrics, summing the sent counters up like the server.
+func deliver(c *Collector, metrics []*model.MetricsDto, totals map[string]int64) {
+	for _, m := range metrics {
+		if m.Type == model.Counter {
+			totals[model.SeriesKey(m.ID, m.Labels)] += *m.Delta
+		}
+	}
+	c.Reported(metrics)
+}
+
+func TestCollector_Reported(t *testing.T) {
+	t.Parallel()
+
+	nop := zerolog.Nop()
+	source := &stubSource{name: "stub", polls: [][]string{{"a"}, {"a"}, {"a"}, {"a"}}}
+	c := NewCollector(1, &nop, source)
+	key := model.SeriesKey("Count", model.Labels{"id": "a"})
+	totals := make(map[string]int64)
+
+	c.Poll()
+	c.Poll()
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(4), totals[key])
+	assert.Zero(t, counterValue(c, key))
+
+	// increments collected between the snapshot and the report are kept for the next one
+	c.Poll()
+	metrics := c.GetAllMetrics()
+	c.Poll()
+	deliver(c, metrics, totals)
+	assert.Equal(t, int64(2), counterValue(c, key))
+
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(8), totals[key])
+}
@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+func init() {
+	Register("disk", newDiskSource)
+}
+
+// diskSource reports usage of mounted filesystems and I/O of their devices.
+type diskSource struct {
+	mountpoints filter
+	fsTypes     filter
+	io          *deltas
+	partitions  func(ctx context.Context, all bool) ([]disk.PartitionStat, error)
+	usage       func(ctx context.Context, path string) (*disk.UsageStat, error)
+	ioCounters  func(ctx context.Context, names ...string) (map[string]disk.IOCountersStat, error)
+}
+
+func newDiskSource(cfg *config.Config) (Source, error) {
+	mountpoints, err := newFilter(cfg.DiskMountpoints)
+	if err != nil {
+		return nil, errs.Wrap(err, "parse mountpoint filter")
+	}
+	fsTypes, err := newFilter(cfg.DiskFSTypes)
+	if err != nil {
+		return nil, errs.Wrap(err, "parse filesystem type filter")
+	}
+
+	return &diskSource{
+		mountpoints: mountpoints,
+		fsTypes:     fsTypes,
+		io:          newDeltas(),
+		partitions:  disk.PartitionsWithContext,
+		usage:       disk.UsageWithContext,
+		ioCounters:  disk.IOCountersWithContext,
+	}, nil
+}
+
+// Name implements Source.
+func (s *diskSource) Name() string {
+	return "disk"
+}
+
+// Collect implements Source.
+//
+// I/O counters are reported as increments since the previous poll, the first poll only sets a baseline.
+func (s *diskSource) Collect(ctx context.Context, sink Sink) error {
+	partitions, err := s.partitions(ctx, false)
+	if err != nil {
+		return errs.Wrap(err, "list partitions")
+	}
+	s.io.begin()
+
+	var devices []string
+	for _, p := range partitions {
+		if !s.mountpoints.match(p.Mountpoint) || !s.fsTypes.match(p.Fstype) {
+			continue
+		}
+
+		// mountpoints the agent has no access to are skipped so that they do not hide the rest.
+		usage, err := s.usage(ctx, p.Mountpoint)
+		if err != nil {
+			continue
+		}
+
+		labels := model.Labels{"mountpoint": p.Mountpoint, "fstype": p.Fstype, "device": p.Device}
+		sink.Gauge("DiskTotal", float64(usage.Total), labels)
+		sink.Gauge("DiskUsed", float64(usage.Used), labels)
+		sink.Gauge("DiskFree", float64(usage.Free), labels)
+		sink.Gauge("DiskInodesTotal", float64(usage.InodesTotal), labels)
+		sink.Gauge("DiskInodesUsed", float64(usage.InodesUsed), labels)
+		sink.Gauge("DiskInodesFree", float64(usage.InodesFree), labels)
+
+		devices = append(devices, p.Device)
+	}
+
+	if len(devices) == 0 {
+		s.io.commit()
+		return nil
+	}
+
+	counters, err := s.ioCounters(ctx, devices...)
+	if err != nil {
+		return errs.Wrap(err, "get I/O counters")
+	}
+	for _, name := range slices.Sorted(maps.Keys(counters)) {
+		c := counters[name]
+		labels := model.Labels{"device": name}
+		sink.Counter("DiskReadBytes", s.io.delta(name+"/read_bytes", c.ReadBytes), labels)
+		sink.Counter("DiskWriteBytes", s.io.delta(name+"/write_bytes", c.WriteBytes), labels)
+		sink.Counter("DiskReadOps", s.io.delta(name+"/read_ops", c.ReadCount), labels)
+		sink.Counter("DiskWriteOps", s.io.delta(name+"/write_ops", c.WriteCount), labels)
+	}
+	s.io.commit()
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+func TestDiskSource_Collect(t *testing.T) {
+	t.Parallel()
+
+	source, err := newDiskSource(&config.Config{
+		DiskMountpoints: config.Filter{Exclude: []string{"/boot"}},
+		DiskFSTypes:     config.Filter{Exclude: []string{"tmpfs"}},
+	})
+	require.NoError(t, err)
+
+	s := source.(*diskSource)
+	s.partitions = func(context.Context, bool) ([]disk.PartitionStat, error) {
+		return []disk.PartitionStat{
+			{Device: "/dev/sda1", Mountpoint: "/", Fstype: "ext4"},
+			{Device: "/dev/sda2", Mountpoint: "/boot", Fstype: "ext4"},
+			{Device: "tmpfs", Mountpoint: "/tmp", Fstype: "tmpfs"},
+			{Device: "/dev/sdb1", Mountpoint: "/mnt/locked", Fstype: "xfs"},
+		}, nil
+	}
+	s.usage = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		if path == "/mnt/locked" {
+			return nil, errors.New("permission denied")
+		}
+		return &disk.UsageStat{Total: 100, Used: 40, Free: 60, InodesTotal: 10, InodesUsed: 1, InodesFree: 9}, nil
+	}
+	reads := uint64(1000)
+	s.ioCounters = func(_ context.Context, names ...string) (map[string]disk.IOCountersStat, error) {
+		assert.Equal(t, []string{"/dev/sda1"}, names)
+		reads += 500
+		return map[string]disk.IOCountersStat{
+			"sda1": {ReadBytes: reads, WriteBytes: 10, ReadCount: 3, WriteCount: 1},
+		}, nil
+	}
+
+	nop := zerolog.Nop()
+	c := NewCollector(1, &nop, s)
+	c.Poll()
+	c.Poll()
+
+	root := model.Labels{"mountpoint": "/", "fstype": "ext4", "device": "/dev/sda1"}
+	sda1 := model.Labels{"device": "sda1"}
+	assert.Equal(t, 100.0, gaugeValue(c, model.SeriesKey("DiskTotal", root)))
+	assert.Equal(t, 9.0, gaugeValue(c, model.SeriesKey("DiskInodesFree", root)))
+	assert.Equal(t, int64(500), counterValue(c, model.SeriesKey("DiskReadBytes", sda1)))
+	assert.Equal(t, int64(0), counterValue(c, model.SeriesKey("DiskWriteBytes", sda1)))
+	assert.Len(t, c.GetAllGaugeMetrics(), 6)
+	assert.Len(t, c.GetAllCounterMetrics(), 4)
+
+	// every report carries only the bytes read since the previous one
+	totals := make(map[string]int64)
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(500), totals[model.SeriesKey("DiskReadBytes", sda1)])
+
+	c.Poll()
+	c.Poll()
+	assert.Equal(t, int64(1000), counterValue(c, model.SeriesKey("DiskReadBytes", sda1)))
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(1500), totals[model.SeriesKey("DiskReadBytes", sda1)])
+	assert.Equal(t, int64(0), totals[model.SeriesKey("DiskWriteBytes", sda1)])
+}
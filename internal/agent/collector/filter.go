@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"path"
+	"slices"
+
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// filter matches names against glob patterns.
+type filter struct {
+	include []string
+	exclude []string
+}
+
+func newFilter(cfg config.Filter) (filter, error) {
+	for _, pattern := range slices.Concat(cfg.Include, cfg.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return filter{}, errs.Wrap(err, "invalid pattern "+pattern)
+		}
+	}
+	return filter{include: cfg.Include, exclude: cfg.Exclude}, nil
+}
+
+// match reports whether the name matches any include pattern and no exclude pattern.
+//
+// Every name is included when there are no include patterns.
+func (f filter) match(name string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, name) {
+		return false
+	}
+	return !matchAny(f.exclude, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// deltas turns cumulative counters into increments between polls.
+type deltas struct {
+	prev map[string]uint64
+	next map[string]uint64
+}
+
+func newDeltas() *deltas {
+	return &deltas{prev: make(map[string]uint64)}
+}
+
+// begin starts a poll discarding values of an unfinished one.
+func (d *deltas) begin() {
+	d.next = make(map[string]uint64, len(d.prev))
+}
+
+// delta returns the increment of the counter since the previous poll.
+//
+// The first value of a counter is a baseline with zero increment, a counter that went backwards is treated as reset.
+func (d *deltas) delta(key string, value uint64) int64 {
+	d.next[key] = value
+
+	prev, ok := d.prev[key]
+	switch {
+	case !ok:
+		return 0
+	case value < prev:
+		return int64(value)
+	default:
+		return int64(value - prev)
+	}
+}
+
+// commit finishes the poll forgetting counters which were not reported.
+func (d *deltas) commit() {
+	d.prev, d.next = d.next, nil
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+)
+
+func TestFilter_match(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  config.Filter
+		in   string
+		want bool
+	}{
+		{name: "Empty filter", cfg: config.Filter{}, in: "/", want: true},
+		{name: "Included", cfg: config.Filter{Include: []string{"/mnt/*"}}, in: "/mnt/data", want: true},
+		{name: "Not included", cfg: config.Filter{Include: []string{"/mnt/*"}}, in: "/", want: false},
+		{name: "Excluded", cfg: config.Filter{Exclude: []string{"tmpfs"}}, in: "tmpfs", want: false},
+		{
+			name: "Exclude wins",
+			cfg:  config.Filter{Include: []string{"/mnt/*"}, Exclude: []string{"/mnt/tmp"}},
+			in:   "/mnt/tmp",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := newFilter(tt.cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.match(tt.in))
+		})
+	}
+}
+
+func TestNewFilter_invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := newFilter(config.Filter{Exclude: []string{"["}})
+	require.Error(t, err)
+}
+
+func TestDeltas(t *testing.T) {
+	t.Parallel()
+
+	d := newDeltas()
+
+	d.begin()
+	assert.Equal(t, int64(0), d.delta("a", 10))
+	d.commit()
+
+	d.begin()
+	assert.Equal(t, int64(5), d.delta("a", 15))
+	assert.Equal(t, int64(0), d.delta("b", 7))
+	d.commit()
+
+	d.begin()
+	assert.Equal(t, int64(3), d.delta("a", 3))
+	d.commit()
+
+	d.begin()
+	assert.Equal(t, int64(0), d.delta("b", 9))
+	d.commit()
+}
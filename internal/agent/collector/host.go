@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+func init() {
+	Register("host", newHostSource)
+}
+
+// hostSource reports load averages, uptime, logged in users and context switches of the host.
+type hostSource struct {
+	switches *deltas
+	avg      func(ctx context.Context) (*load.AvgStat, error)
+	misc     func(ctx context.Context) (*load.MiscStat, error)
+	uptime   func(ctx context.Context) (uint64, error)
+	bootTime func(ctx context.Context) (uint64, error)
+	users    func(ctx context.Context) ([]host.UserStat, error)
+}
+
+func newHostSource(*config.Config) (Source, error) {
+	return &hostSource{
+		switches: newDeltas(),
+		avg:      load.AvgWithContext,
+		misc:     load.MiscWithContext,
+		uptime:   host.UptimeWithContext,
+		bootTime: host.BootTimeWithContext,
+		users:    host.UsersWithContext,
+	}, nil
+}
+
+// Name implements Source.
+func (s *hostSource) Name() string {
+	return "host"
+}
+
+// Collect implements Source.
+//
+// Logged in users and context switches are not available on every platform and are skipped when missing.
+// Context switches are reported as increments since the previous poll, the first poll only sets a baseline.
+func (s *hostSource) Collect(ctx context.Context, sink Sink) error {
+	avg, err := s.avg(ctx)
+	if err != nil {
+		return errs.Wrap(err, "get load average")
+	}
+	sink.Gauge("LoadAverage1", avg.Load1, nil)
+	sink.Gauge("LoadAverage5", avg.Load5, nil)
+	sink.Gauge("LoadAverage15", avg.Load15, nil)
+
+	uptime, err := s.uptime(ctx)
+	if err != nil {
+		return errs.Wrap(err, "get uptime")
+	}
+	sink.Gauge("Uptime", float64(uptime), nil)
+
+	bootTime, err := s.bootTime(ctx)
+	if err != nil {
+		return errs.Wrap(err, "get boot time")
+	}
+	sink.Gauge("BootTime", float64(bootTime), nil)
+
+	if users, err := s.users(ctx); err == nil {
+		sink.Gauge("LoggedInUsers", float64(len(users)), nil)
+	}
+
+	s.switches.begin()
+	if misc, err := s.misc(ctx); err == nil && misc.Ctxt > 0 {
+		sink.Counter("ContextSwitches", s.switches.delta("ctxt", uint64(misc.Ctxt)), nil)
+	}
+	s.switches.commit()
+
+	return nil
+}
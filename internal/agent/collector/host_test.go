@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+)
+
+func TestHostSource_Collect(t *testing.T) {
+	t.Parallel()
+
+	source, err := newHostSource(&config.Config{})
+	require.NoError(t, err)
+
+	ctxt := 1000
+	s := source.(*hostSource)
+	s.avg = func(context.Context) (*load.AvgStat, error) {
+		return &load.AvgStat{Load1: 1.5, Load5: 1, Load15: 0.5}, nil
+	}
+	s.misc = func(context.Context) (*load.MiscStat, error) {
+		ctxt += 250
+		return &load.MiscStat{Ctxt: ctxt}, nil
+	}
+	s.uptime = func(context.Context) (uint64, error) {
+		return 3600, nil
+	}
+	s.bootTime = func(context.Context) (uint64, error) {
+		return 1700000000, nil
+	}
+	s.users = func(context.Context) ([]host.UserStat, error) {
+		return []host.UserStat{{User: "root"}, {User: "dev"}}, nil
+	}
+
+	nop := zerolog.Nop()
+	c := NewCollector(1, &nop, s)
+	c.Poll()
+	c.Poll()
+
+	assert.Equal(t, 1.5, gaugeValue(c, "LoadAverage1"))
+	assert.Equal(t, 0.5, gaugeValue(c, "LoadAverage15"))
+	assert.Equal(t, 3600.0, gaugeValue(c, "Uptime"))
+	assert.Equal(t, 1700000000.0, gaugeValue(c, "BootTime"))
+	assert.Equal(t, 2.0, gaugeValue(c, "LoggedInUsers"))
+	assert.Equal(t, int64(250), counterValue(c, "ContextSwitches"))
+
+	totals := make(map[string]int64)
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(250), totals["ContextSwitches"])
+
+	s.users = func(context.Context) ([]host.UserStat, error) {
+		return nil, errors.New("utmp is missing")
+	}
+	c.Poll()
+	assert.Len(t, c.GetAllGaugeMetrics(), 5, "users series is dropped")
+	assert.Equal(t, int64(250), counterValue(c, "ContextSwitches"), "only the switches since the last report")
+
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(500), totals["ContextSwitches"])
+}
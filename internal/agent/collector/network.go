@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"context"
+	"slices"
+
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// tcpStates are always reported so that a state without connections drops to zero.
+var tcpStates = []string{
+	"ESTABLISHED",
+	"SYN_SENT",
+	"SYN_RECV",
+	"FIN_WAIT1",
+	"FIN_WAIT2",
+	"TIME_WAIT",
+	"CLOSE",
+	"CLOSE_WAIT",
+	"LAST_ACK",
+	"LISTEN",
+	"CLOSING",
+}
+
+func init() {
+	Register("network", newNetworkSource)
+}
+
+// networkSource reports traffic of network interfaces and TCP connections by state.
+type networkSource struct {
+	interfaces  filter
+	io          *deltas
+	ioCounters  func(ctx context.Context, pernic bool) ([]net.IOCountersStat, error)
+	connections func(ctx context.Context, kind string) ([]net.ConnectionStat, error)
+}
+
+func newNetworkSource(cfg *config.Config) (Source, error) {
+	interfaces, err := newFilter(cfg.NetInterfaces)
+	if err != nil {
+		return nil, errs.Wrap(err, "parse interface filter")
+	}
+
+	return &networkSource{
+		interfaces:  interfaces,
+		io:          newDeltas(),
+		ioCounters:  net.IOCountersWithContext,
+		connections: net.ConnectionsWithoutUidsWithContext,
+	}, nil
+}
+
+// Name implements Source.
+func (s *networkSource) Name() string {
+	return "network"
+}
+
+// Collect implements Source.
+//
+// Interface counters are reported as increments since the previous poll, the first poll only sets a baseline.
+func (s *networkSource) Collect(ctx context.Context, sink Sink) error {
+	counters, err := s.ioCounters(ctx, true)
+	if err != nil {
+		return errs.Wrap(err, "get interface counters")
+	}
+
+	s.io.begin()
+	for _, c := range counters {
+		if !s.interfaces.match(c.Name) {
+			continue
+		}
+
+		labels := model.Labels{"interface": c.Name}
+		sink.Counter("NetBytesRecv", s.io.delta(c.Name+"/bytes_recv", c.BytesRecv), labels)
+		sink.Counter("NetBytesSent", s.io.delta(c.Name+"/bytes_sent", c.BytesSent), labels)
+		sink.Counter("NetPacketsRecv", s.io.delta(c.Name+"/packets_recv", c.PacketsRecv), labels)
+		sink.Counter("NetPacketsSent", s.io.delta(c.Name+"/packets_sent", c.PacketsSent), labels)
+		sink.Counter("NetErrIn", s.io.delta(c.Name+"/err_in", c.Errin), labels)
+		sink.Counter("NetErrOut", s.io.delta(c.Name+"/err_out", c.Errout), labels)
+		sink.Counter("NetDropIn", s.io.delta(c.Name+"/drop_in", c.Dropin), labels)
+		sink.Counter("NetDropOut", s.io.delta(c.Name+"/drop_out", c.Dropout), labels)
+	}
+	s.io.commit()
+
+	conns, err := s.connections(ctx, "tcp")
+	if err != nil {
+		return errs.Wrap(err, "list TCP connections")
+	}
+
+	states := slices.Clone(tcpStates)
+	byState := make(map[string]int, len(states))
+	for _, conn := range conns {
+		if conn.Status == "" {
+			continue
+		}
+		if !slices.Contains(states, conn.Status) {
+			states = append(states, conn.Status)
+		}
+		byState[conn.Status]++
+	}
+	for _, state := range states {
+		sink.Gauge("TCPConnections", float64(byState[state]), model.Labels{"state": state})
+	}
+
+	return nil
+}
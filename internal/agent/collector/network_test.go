@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+func TestNetworkSource_Collect(t *testing.T) {
+	t.Parallel()
+
+	source, err := newNetworkSource(&config.Config{
+		NetInterfaces: config.Filter{Exclude: []string{"lo", "veth*"}},
+	})
+	require.NoError(t, err)
+
+	s := source.(*networkSource)
+	recv := uint64(100)
+	s.ioCounters = func(context.Context, bool) ([]net.IOCountersStat, error) {
+		recv += 50
+		return []net.IOCountersStat{
+			{Name: "lo", BytesRecv: recv},
+			{Name: "veth1a2b", BytesRecv: recv},
+			{Name: "eth0", BytesRecv: recv, Errin: 2},
+		}, nil
+	}
+	s.connections = func(context.Context, string) ([]net.ConnectionStat, error) {
+		return []net.ConnectionStat{
+			{Status: "ESTABLISHED"},
+			{Status: "ESTABLISHED"},
+			{Status: "LISTEN"},
+			{Status: "NONE"},
+		}, nil
+	}
+
+	nop := zerolog.Nop()
+	c := NewCollector(1, &nop, s)
+	c.Poll()
+	c.Poll()
+
+	eth0 := model.Labels{"interface": "eth0"}
+	assert.Equal(t, int64(50), counterValue(c, model.SeriesKey("NetBytesRecv", eth0)))
+	assert.Equal(t, int64(0), counterValue(c, model.SeriesKey("NetErrIn", eth0)))
+	assert.Len(t, c.GetAllCounterMetrics(), 8)
+
+	// every report carries only the bytes received since the previous one
+	totals := make(map[string]int64)
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(50), totals[model.SeriesKey("NetBytesRecv", eth0)])
+
+	c.Poll()
+	c.Poll()
+	c.Poll()
+	assert.Equal(t, int64(150), counterValue(c, model.SeriesKey("NetBytesRecv", eth0)))
+	deliver(c, c.GetAllMetrics(), totals)
+	assert.Equal(t, int64(200), totals[model.SeriesKey("NetBytesRecv", eth0)])
+	assert.Equal(t, int64(0), totals[model.SeriesKey("NetErrIn", eth0)])
+
+	state := func(s string) string {
+		return model.SeriesKey("TCPConnections", model.Labels{"state": s})
+	}
+	assert.Equal(t, 2.0, gaugeValue(c, state("ESTABLISHED")))
+	assert.Equal(t, 1.0, gaugeValue(c, state("LISTEN")))
+	assert.Equal(t, 1.0, gaugeValue(c, state("NONE")))
+	assert.Len(t, c.GetAllGaugeMetrics(), len(tcpStates)+1)
+}
@@ -0,0 +1,227 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+func init() {
+	Register("process", newProcessSource)
+}
+
+// processStat is a snapshot of a process, OpenFDs is negative when the agent may not read them.
+type processStat struct {
+	Name       string
+	CreateTime time.Time
+	CPUTime    time.Duration
+	RSS        uint64
+	OpenFDs    int32
+	Threads    int32
+}
+
+type cpuSample struct {
+	createTime time.Time
+	cpuTime    time.Duration
+	at         time.Time
+}
+
+// processSource reports resource usage of processes matching the configured rules.
+type processSource struct {
+	rules []config.ProcessRule
+	cpu   map[int32]cpuSample
+	pids  func(ctx context.Context) ([]int32, error)
+	name  func(ctx context.Context, pid int32) (string, error)
+	stat  func(ctx context.Context, pid int32) (processStat, error)
+	now   func() time.Time
+}
+
+func newProcessSource(cfg *config.Config) (Source, error) {
+	if len(cfg.Processes) == 0 {
+		return nil, nil
+	}
+	for _, rule := range cfg.Processes {
+		if _, err := path.Match(rule.Name, ""); err != nil {
+			return nil, errs.Wrap(err, "invalid process name pattern "+rule.Name)
+		}
+	}
+
+	return &processSource{
+		rules: cfg.Processes,
+		cpu:   make(map[int32]cpuSample),
+		pids:  process.PidsWithContext,
+		name:  processName,
+		stat:  statProcess,
+		now:   time.Now,
+	}, nil
+}
+
+// Name implements Source.
+func (s *processSource) Name() string {
+	return "process"
+}
+
+// Collect implements Source.
+//
+// CPU percent is measured since the previous poll, or since the process start when it is seen for the first time.
+// Processes which exit between polls are skipped and stop being reported.
+func (s *processSource) Collect(ctx context.Context, sink Sink) error {
+	pids, err := s.match(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	cpu := make(map[int32]cpuSample, len(pids))
+	for _, pid := range pids {
+		stat, err := s.stat(ctx, pid)
+		if err != nil {
+			continue
+		}
+
+		sample := cpuSample{createTime: stat.CreateTime, cpuTime: stat.CPUTime, at: now}
+		cpu[pid] = sample
+
+		since := stat.CreateTime
+		cpuTime := stat.CPUTime
+		if prev, ok := s.cpu[pid]; ok && prev.createTime.Equal(stat.CreateTime) {
+			since = prev.at
+			cpuTime -= prev.cpuTime
+		}
+		var cpuPercent float64
+		if elapsed := now.Sub(since); elapsed > 0 {
+			cpuPercent = cpuTime.Seconds() / elapsed.Seconds() * 100
+		}
+
+		labels := model.Labels{"process": stat.Name, "pid": strconv.Itoa(int(pid))}
+		sink.Gauge("ProcessCPUPercent", cpuPercent, labels)
+		sink.Gauge("ProcessRSS", float64(stat.RSS), labels)
+		sink.Gauge("ProcessThreads", float64(stat.Threads), labels)
+		sink.Gauge("ProcessUptime", now.Sub(stat.CreateTime).Seconds(), labels)
+		if stat.OpenFDs >= 0 {
+			sink.Gauge("ProcessOpenFDs", float64(stat.OpenFDs), labels)
+		}
+	}
+	s.cpu = cpu
+
+	return nil
+}
+
+// match returns PIDs of processes matching any rule, missing PID files are ignored.
+func (s *processSource) match(ctx context.Context) ([]int32, error) {
+	var (
+		matched []int32
+		seen    = make(map[int32]bool)
+		all     []int32
+	)
+	add := func(pid int32) {
+		if !seen[pid] {
+			seen[pid] = true
+			matched = append(matched, pid)
+		}
+	}
+
+	for _, rule := range s.rules {
+		if rule.PIDFile != "" {
+			pid, err := readPIDFile(rule.PIDFile)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			add(pid)
+			continue
+		}
+
+		if all == nil {
+			var err error
+			all, err = s.pids(ctx)
+			if err != nil {
+				return nil, errs.Wrap(err, "list processes")
+			}
+		}
+		for _, pid := range all {
+			name, err := s.name(ctx, pid)
+			if err != nil {
+				continue
+			}
+			if ok, _ := path.Match(rule.Name, name); ok {
+				add(pid)
+			}
+		}
+	}
+
+	return matched, nil
+}
+
+func readPIDFile(name string) (int32, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return 0, errs.Wrap(err, "read PID file")
+	}
+
+	pid, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil {
+		return 0, errs.Wrap(err, "parse PID file "+name)
+	}
+	return int32(pid), nil
+}
+
+func processName(ctx context.Context, pid int32) (string, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return "", errs.Wrap(err, "find process")
+	}
+	return p.NameWithContext(ctx)
+}
+
+func statProcess(ctx context.Context, pid int32) (processStat, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return processStat{}, errs.Wrap(err, "find process")
+	}
+
+	name, err := p.NameWithContext(ctx)
+	if err != nil {
+		return processStat{}, errs.Wrap(err, "get process name")
+	}
+	createTime, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		return processStat{}, errs.Wrap(err, "get process create time")
+	}
+	times, err := p.TimesWithContext(ctx)
+	if err != nil {
+		return processStat{}, errs.Wrap(err, "get process cpu times")
+	}
+	mem, err := p.MemoryInfoWithContext(ctx)
+	if err != nil {
+		return processStat{}, errs.Wrap(err, "get process memory")
+	}
+	threads, err := p.NumThreadsWithContext(ctx)
+	if err != nil {
+		return processStat{}, errs.Wrap(err, "get process threads")
+	}
+	fds, err := p.NumFDsWithContext(ctx)
+	if err != nil {
+		fds = -1
+	}
+
+	return processStat{
+		Name:       name,
+		CreateTime: time.UnixMilli(createTime),
+		CPUTime:    time.Duration((times.User + times.System) * float64(time.Second)),
+		RSS:        mem.RSS,
+		OpenFDs:    fds,
+		Threads:    threads,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+func TestNewProcessSource(t *testing.T) {
+	t.Parallel()
+
+	source, err := newProcessSource(&config.Config{})
+	require.NoError(t, err)
+	assert.Nil(t, source)
+
+	_, err = newProcessSource(&config.Config{Processes: []config.ProcessRule{{Name: "["}}})
+	require.Error(t, err)
+}
+
+func TestProcessSource_Collect(t *testing.T) {
+	t.Parallel()
+
+	pidFile := filepath.Join(t.TempDir(), "db.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte("30\n"), 0o600))
+
+	source, err := newProcessSource(&config.Config{Processes: []config.ProcessRule{
+		{Name: "nginx*"},
+		{PIDFile: pidFile},
+		{PIDFile: filepath.Join(t.TempDir(), "missing.pid")},
+	}})
+	require.NoError(t, err)
+
+	start := time.Unix(1000, 0)
+	now := start.Add(10 * time.Second)
+	running := map[int32]processStat{
+		10: {Name: "nginx", CreateTime: start, CPUTime: time.Second, RSS: 100, OpenFDs: 5, Threads: 2},
+		20: {Name: "sshd", CreateTime: start},
+		30: {Name: "postgres", CreateTime: start, OpenFDs: -1},
+	}
+
+	s := source.(*processSource)
+	s.now = func() time.Time { return now }
+	s.pids = func(context.Context) ([]int32, error) {
+		return []int32{10, 11, 20, 30}, nil
+	}
+	s.name = func(_ context.Context, pid int32) (string, error) {
+		if pid == 11 {
+			return "nginx-worker", nil
+		}
+		stat, ok := running[pid]
+		if !ok {
+			return "", errors.New("process not found")
+		}
+		return stat.Name, nil
+	}
+	s.stat = func(_ context.Context, pid int32) (processStat, error) {
+		stat, ok := running[pid]
+		if !ok {
+			return processStat{}, errors.New("process not found")
+		}
+		return stat, nil
+	}
+
+	nop := zerolog.Nop()
+	c := NewCollector(1, &nop, s)
+	c.Poll()
+
+	nginx := model.Labels{"process": "nginx", "pid": "10"}
+	postgres := model.Labels{"process": "postgres", "pid": "30"}
+	assert.InDelta(t, 10.0, gaugeValue(c, model.SeriesKey("ProcessCPUPercent", nginx)), 1e-9)
+	assert.Equal(t, 100.0, gaugeValue(c, model.SeriesKey("ProcessRSS", nginx)))
+	assert.Equal(t, 5.0, gaugeValue(c, model.SeriesKey("ProcessOpenFDs", nginx)))
+	assert.Equal(t, 10.0, gaugeValue(c, model.SeriesKey("ProcessUptime", nginx)))
+	assert.Equal(t, 2.0, gaugeValue(c, model.SeriesKey("ProcessThreads", nginx)))
+	assert.Len(t, c.GetAllGaugeMetrics(), 9, "worker exited and postgres has no fds")
+
+	now = now.Add(5 * time.Second)
+	running[10] = processStat{Name: "nginx", CreateTime: start, CPUTime: 3 * time.Second}
+	delete(running, 30)
+	c.Poll()
+
+	assert.InDelta(t, 40.0, gaugeValue(c, model.SeriesKey("ProcessCPUPercent", nginx)), 1e-9)
+	assert.Zero(t, gaugeValue(c, model.SeriesKey("ProcessRSS", postgres)))
+	assert.Len(t, c.GetAllGaugeMetrics(), 5)
+}
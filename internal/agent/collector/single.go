@@ -1,30 +1,30 @@
 package collector
 
 import (
+	"context"
 	"time"
 
-	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
 )
 
-// GeneralMetrics holds general application metrics.
-//
-// generate:reset
-type GeneralMetrics struct {
-	PollCount   *model.Metrics[int64]
-	RandomValue *model.Metrics[float64]
+func init() {
+	Register("general", func(*config.Config) (Source, error) {
+		return generalSource{}, nil
+	})
 }
 
-// NewGeneralMetrics initializes and returns a new GeneralMetrics instance.
-func NewGeneralMetrics() *GeneralMetrics {
-	return &GeneralMetrics{
-		PollCount:   model.NewCounterMetric("PollCount"),
-		RandomValue: model.NewGaugeMetric("RandomValue"),
-	}
+// generalSource reports the poll counter and a random value.
+type generalSource struct{}
+
+// Name implements Source.
+func (generalSource) Name() string {
+	return "general"
 }
 
-func (c *Collector) updateGeneralMetrics() error {
-	c.generalMetrics.PollCount.Value++
-	c.generalMetrics.RandomValue.Value = float64(time.Now().UnixNano()%100) + 1
+// Collect implements Source.
+func (generalSource) Collect(_ context.Context, sink Sink) error {
+	sink.Counter("PollCount", 1, nil)
+	sink.Gauge("RandomValue", float64(time.Now().UnixNano()%100)+1, nil)
 
 	return nil
 }
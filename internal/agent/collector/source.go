@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// Sink receives metrics collected by a source.
+//
+// Gauges replace the previous value of the series, counters add the delta to it.
+type Sink interface {
+	Gauge(id string, value float64, labels model.Labels)
+	Counter(id string, delta int64, labels model.Labels)
+}
+
+// Source collects a group of metrics on every poll.
+//
+// Series a source stops reporting are no longer sent, so sources may report a varying set of series.
+type Source interface {
+	Name() string
+	Collect(ctx context.Context, sink Sink) error
+}
+
+// Factory creates a source from the agent config, nil source means there is nothing to collect.
+type Factory func(cfg *config.Config) (Source, error)
+
+type registration struct {
+	name    string
+	factory Factory
+}
+
+var (
+	registry   []registration
+	registryMu sync.RWMutex
+)
+
+// Register makes a source available by name, it is meant to be called from init functions.
+//
+// Register panics if a source with the name is already registered.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if slices.ContainsFunc(registry, func(r registration) bool { return r.name == name }) {
+		panic("collector: source " + name + " is already registered")
+	}
+	registry = append(registry, registration{name: name, factory: factory})
+}
+
+// Registered returns names of registered sources in registration order.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for _, r := range registry {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+// NewSources creates sources enabled by the config.
+//
+// All registered sources are enabled unless the config lists them, disabled ones are skipped.
+func NewSources(cfg *config.Config) ([]Source, error) {
+	registered := Registered()
+	for _, name := range slices.Concat(cfg.Collectors, cfg.DisabledCollectors) {
+		if !slices.Contains(registered, name) {
+			return nil, fmt.Errorf("unknown collector %q", name)
+		}
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var sources []Source
+	for _, r := range registry {
+		if len(cfg.Collectors) > 0 && !slices.Contains(cfg.Collectors, r.name) {
+			continue
+		}
+		if slices.Contains(cfg.DisabledCollectors, r.name) {
+			continue
+		}
+
+		source, err := r.factory(cfg)
+		if err != nil {
+			return nil, errs.Wrap(err, "create collector "+r.name)
+		}
+		if source != nil {
+			sources = append(sources, source)
+		}
+	}
+	return sources, nil
+}
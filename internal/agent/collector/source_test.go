@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+)
+
+func sourceNames(sources []Source) []string {
+	names := make([]string, 0, len(sources))
+	for _, s := range sources {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func TestNewSources(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "All registered by default",
+			cfg:  &config.Config{},
+			// process source has nothing to watch without rules.
+			want: slices.DeleteFunc(Registered(), func(name string) bool { return name == "process" }),
+		},
+		{
+			name: "Only enabled",
+			cfg:  &config.Config{Collectors: []string{"memory", "general"}},
+			want: []string{"memory", "general"},
+		},
+		{
+			name: "Disabled are skipped",
+			cfg: &config.Config{
+				Collectors:         []string{"memory", "general"},
+				DisabledCollectors: []string{"memory"},
+			},
+			want: []string{"general"},
+		},
+		{
+			name:    "Unknown enabled",
+			cfg:     &config.Config{Collectors: []string{"unknown"}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown disabled",
+			cfg:     &config.Config{DisabledCollectors: []string{"unknown"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sources, err := NewSources(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.want, sourceNames(sources))
+		})
+	}
+}
+
+func TestRegister_duplicate(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		Register("memory", nil)
+	})
+}
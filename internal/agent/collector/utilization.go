@@ -1,58 +1,46 @@
 package collector
 
 import (
-	"runtime"
+	"context"
 	"strconv"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/mem"
-	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 )
 
-// UtilizationMetrics holds system utilization metrics.
-//
-// generate:reset
-type UtilizationMetrics struct {
-	TotalMemory    *model.Metrics[float64]
-	FreeMemory     *model.Metrics[float64]
-	CPUUtilization []*model.Metrics[float64]
+func init() {
+	Register("utilization", func(*config.Config) (Source, error) {
+		return utilizationSource{}, nil
+	})
 }
 
-// NewUtilizationMetrics initializes and returns a new UtilizationMetrics instance.
-func NewUtilizationMetrics() *UtilizationMetrics {
-	return &UtilizationMetrics{
-		TotalMemory: model.NewGaugeMetric("TotalMemory"),
-		FreeMemory:  model.NewGaugeMetric("FreeMemory"),
-		CPUUtilization: func() []*model.Metrics[float64] {
-			metrics := make([]*model.Metrics[float64], 0, runtime.NumCPU())
-			for i := 0; i < runtime.NumCPU(); i++ {
-				metrics = append(metrics, model.NewGaugeMetric("CPUutilization"+strconv.Itoa(i)))
-			}
-			return metrics
-		}(),
-	}
+// utilizationSource reports system memory and per CPU utilization.
+type utilizationSource struct{}
+
+// Name implements Source.
+func (utilizationSource) Name() string {
+	return "utilization"
 }
 
-func (c *Collector) updateUtilizationMetrics() error {
-	memoryUsage, err := mem.VirtualMemory()
+// Collect implements Source.
+func (utilizationSource) Collect(ctx context.Context, sink Sink) error {
+	memoryUsage, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
 		return errs.Wrap(err, "update memory utilization")
 	}
 
-	c.utilizationMetrics.TotalMemory.Value = float64(memoryUsage.Total)
-	c.utilizationMetrics.FreeMemory.Value = float64(memoryUsage.Free)
+	sink.Gauge("TotalMemory", float64(memoryUsage.Total), nil)
+	sink.Gauge("FreeMemory", float64(memoryUsage.Free), nil)
 
-	cpuUsage, err := cpu.Percent(time.Duration(0), true)
+	cpuUsage, err := cpu.PercentWithContext(ctx, time.Duration(0), true)
 	if err != nil {
 		return errs.Wrap(err, "update cpu utilization")
 	}
 	for i, cpuPercent := range cpuUsage {
-		if i >= len(c.utilizationMetrics.CPUUtilization) {
-			break
-		}
-		c.utilizationMetrics.CPUUtilization[i].Value = cpuPercent
+		sink.Gauge("CPUutilization"+strconv.Itoa(i), cpuPercent, nil)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"os"
 	"strings"
@@ -17,16 +18,55 @@ const (
 	defaultBatchSize      = 3
 )
 
+// Transports to send metrics with.
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
 // Config holds the configuration settings for the agent.
+//
+// Metrics are sent to GRPCAddr when Transport is grpc, otherwise to ServerAddr over HTTP.
+// HTTP payloads are encrypted with the public key from CryptoKey file when it is set.
+// The server is reached over TLS trusting the CA from TLSCAFile when any TLS file is set,
+// the certificate from TLSCertFile authenticates the agent with mutual TLS.
+// Only metric sources listed in Collectors are polled when it is set, DisabledCollectors are never polled.
+// Disk metrics are reported for filesystems matching DiskMountpoints and DiskFSTypes,
+// network metrics for interfaces matching NetInterfaces, process metrics for processes matching Processes.
 type Config struct {
-	ServerAddr        string
-	PollIntervalSec   int
-	ReportIntervalSec int
-	CompressionType   string
-	Retry             *retry.Config
-	SecureKey         string
-	RateLimit         int
-	BatchSize         int
+	ServerAddr         string
+	GRPCAddr           string
+	Transport          string
+	PollIntervalSec    int
+	ReportIntervalSec  int
+	CompressionType    string
+	Retry              *retry.Config
+	SecureKey          string
+	CryptoKey          string
+	TLSCAFile          string
+	TLSCertFile        string
+	TLSKeyFile         string
+	RateLimit          int
+	BatchSize          int
+	InstanceID         string
+	Collectors         []string
+	DisabledCollectors []string
+	DiskMountpoints    Filter
+	DiskFSTypes        Filter
+	NetInterfaces      Filter
+	Processes          []ProcessRule
+}
+
+// ProcessRule selects processes whose name matches the Name glob pattern or whose PID is stored in PIDFile.
+type ProcessRule struct {
+	Name    string
+	PIDFile string
+}
+
+// Filter selects names matching any Include glob pattern and no Exclude one, empty Include selects all names.
+type Filter struct {
+	Include []string
+	Exclude []string
 }
 
 // NewConfig creates a new Config with cli args or default values.
@@ -37,19 +77,73 @@ func NewConfig() (*Config, error) {
 	reportIntervalFlag := flags.Int("r", defaultReportInterval, "интервал отправки метрик на сервер, сек. ")
 	compressionTypeFlag := flags.String("c", "", "тип сжатия при отправке метрик на сервер")
 	secureKeyFlag := flags.String("k", "", "ключ для подписи сигнатуры сообщений")
+	cryptoKeyFlag := flags.String("crypto-key", "", "путь к файлу с публичным ключом для шифрования сообщений")
+	tlsCAFlag := flags.String("tls-ca", "", "путь к файлу с сертификатом CA сервера")
+	tlsCertFlag := flags.String("tls-cert", "", "путь к файлу с клиентским сертификатом агента (mTLS)")
+	tlsKeyFlag := flags.String("tls-key", "", "путь к файлу с приватным ключом клиентского сертификата агента")
 	rateLimitFlag := flags.Int("l", 1, "максимальное число одновременных запросов к серверу")
+	transportFlag := flags.String("transport", TransportHTTP, "протокол отправки метрик на сервер (http, grpc)")
+	grpcAddrFlag := flags.String("grpc-address", "", "адрес gRPC сервера в формате ip:port")
+	instanceIDFlag := flags.String("i", "", "идентификатор экземпляра агента, по умолчанию имя хоста")
+	collectorsFlag := flags.String("collectors", "", "включенные источники метрик через запятую, по умолчанию все")
+	disabledCollectorsFlag := flags.String("disable-collectors", "", "отключенные источники метрик через запятую")
+	diskMountpointsFlag := flags.String("disk-mountpoints", "", "шаблоны точек монтирования для метрик дисков")
+	diskExcludeMountpointsFlag := flags.String("disk-exclude-mountpoints", "", "исключаемые точки монтирования")
+	diskFSTypesFlag := flags.String("disk-fstypes", "", "шаблоны типов файловых систем для метрик дисков")
+	diskExcludeFSTypesFlag := flags.String(
+		"disk-exclude-fstypes", "tmpfs,devtmpfs,overlay,squashfs", "исключаемые типы файловых систем",
+	)
+	netInterfacesFlag := flags.String("net-interfaces", "", "шаблоны сетевых интерфейсов для сетевых метрик")
+	netExcludeInterfacesFlag := flags.String("net-exclude-interfaces", "lo,veth*", "исключаемые сетевые интерфейсы")
+	processesFlag := flags.String("processes", "", "отслеживаемые процессы (name:шаблон или pidfile:путь)")
 
 	if err := flags.Parse(os.Args[1:]); err != nil {
 		return nil, errs.Wrap(err, "parse flags")
 	}
 
+	tlsCAFile := pkg.GetEnv("TLS_CA", *tlsCAFlag)
+	tlsCertFile := pkg.GetEnv("TLS_CERT", *tlsCertFlag)
+	tlsKeyFile := pkg.GetEnv("TLS_KEY", *tlsKeyFlag)
+	if (tlsCertFile == "") != (tlsKeyFile == "") {
+		return nil, errs.Wrap(errors.New("TLS certificate and key must be set together"), "parse TLS config")
+	}
+
 	serverAddr := pkg.GetEnv("ADDRESS", *serverAddrFlag)
 	if !strings.HasPrefix(serverAddr, "http://") && !strings.HasPrefix(serverAddr, "https://") {
-		serverAddr = "http://" + serverAddr
+		if tlsCAFile != "" || tlsCertFile != "" {
+			serverAddr = "https://" + serverAddr
+		} else {
+			serverAddr = "http://" + serverAddr
+		}
+	}
+
+	transport := pkg.GetEnv("TRANSPORT", *transportFlag)
+	if transport != TransportHTTP && transport != TransportGRPC {
+		return nil, errs.Wrap(errors.New("unknown transport "+transport), "parse transport")
+	}
+	grpcAddr := pkg.GetEnv("GRPC_ADDRESS", *grpcAddrFlag)
+	if transport == TransportGRPC && grpcAddr == "" {
+		return nil, errs.Wrap(errors.New("gRPC address is required for grpc transport"), "parse transport")
+	}
+
+	instanceID := pkg.GetEnv("INSTANCE_ID", *instanceIDFlag)
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, errs.Wrap(err, "get hostname")
+		}
+		instanceID = hostname
+	}
+
+	processes, err := parseProcessRules(splitList(pkg.GetEnv("PROCESSES", *processesFlag)))
+	if err != nil {
+		return nil, errs.Wrap(err, "parse process rules")
 	}
 
 	return &Config{
 		ServerAddr:        serverAddr,
+		GRPCAddr:          grpcAddr,
+		Transport:         transport,
 		PollIntervalSec:   pkg.GetEnv("POLL_INTERVAL", *pollIntervalFlag),
 		ReportIntervalSec: pkg.GetEnv("REPORT_INTERVAL", *reportIntervalFlag),
 		CompressionType:   pkg.GetEnv("COMPRESSION_TYPE", *compressionTypeFlag),
@@ -57,8 +151,68 @@ func NewConfig() (*Config, error) {
 			MaxRetries:         retry.DefaultRetries,
 			LinearBackoffMilli: retry.DefaultLinearBackoffMilli,
 		},
-		SecureKey: pkg.GetEnv("KEY", *secureKeyFlag),
-		RateLimit: pkg.GetEnv("RATE_LIMIT", *rateLimitFlag),
-		BatchSize: defaultBatchSize,
+		SecureKey:          pkg.GetEnv("KEY", *secureKeyFlag),
+		CryptoKey:          pkg.GetEnv("CRYPTO_KEY", *cryptoKeyFlag),
+		TLSCAFile:          tlsCAFile,
+		TLSCertFile:        tlsCertFile,
+		TLSKeyFile:         tlsKeyFile,
+		RateLimit:          pkg.GetEnv("RATE_LIMIT", *rateLimitFlag),
+		BatchSize:          defaultBatchSize,
+		InstanceID:         instanceID,
+		Collectors:         splitList(pkg.GetEnv("COLLECTORS", *collectorsFlag)),
+		DisabledCollectors: splitList(pkg.GetEnv("DISABLE_COLLECTORS", *disabledCollectorsFlag)),
+		DiskMountpoints: Filter{
+			Include: splitList(pkg.GetEnv("DISK_MOUNTPOINTS", *diskMountpointsFlag)),
+			Exclude: splitList(pkg.GetEnv("DISK_EXCLUDE_MOUNTPOINTS", *diskExcludeMountpointsFlag)),
+		},
+		DiskFSTypes: Filter{
+			Include: splitList(pkg.GetEnv("DISK_FSTYPES", *diskFSTypesFlag)),
+			Exclude: splitList(pkg.GetEnv("DISK_EXCLUDE_FSTYPES", *diskExcludeFSTypesFlag)),
+		},
+		NetInterfaces: Filter{
+			Include: splitList(pkg.GetEnv("NET_INTERFACES", *netInterfacesFlag)),
+			Exclude: splitList(pkg.GetEnv("NET_EXCLUDE_INTERFACES", *netExcludeInterfacesFlag)),
+		},
+		Processes: processes,
 	}, nil
 }
+
+// TLS reports whether the server is reached over TLS.
+func (c *Config) TLS() bool {
+	return strings.HasPrefix(c.ServerAddr, "https://") || c.TLSCAFile != "" || c.TLSCertFile != ""
+}
+
+// splitList splits a comma-separated list skipping empty items.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseProcessRules parses rules in name:pattern or pidfile:path form, items without a kind are name patterns.
+func parseProcessRules(items []string) ([]ProcessRule, error) {
+	rules := make([]ProcessRule, 0, len(items))
+	for _, item := range items {
+		kind, value, found := strings.Cut(item, ":")
+		if !found {
+			kind, value = "name", item
+		}
+		if value == "" {
+			return nil, errors.New("empty process rule " + item)
+		}
+
+		switch kind {
+		case "name":
+			rules = append(rules, ProcessRule{Name: value})
+		case "pidfile":
+			rules = append(rules, ProcessRule{PIDFile: value})
+		default:
+			return nil, errors.New("unknown process rule kind " + kind)
+		}
+	}
+	return rules, nil
+}
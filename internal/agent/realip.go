@@ -0,0 +1,39 @@
+package agent
+
+import (
+	"net"
+	"net/url"
+
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// serverAddr returns the host:port the agent sends metrics to with its transport.
+func (a *Agent) serverAddr() string {
+	if a.cfg.Transport == config.TransportGRPC {
+		return a.cfg.GRPCAddr
+	}
+
+	u, err := url.Parse(a.cfg.ServerAddr)
+	if err != nil {
+		return a.cfg.ServerAddr
+	}
+	return u.Host
+}
+
+// outboundIP returns the address of the interface used to reach the server.
+//
+// Connecting a UDP socket only selects the route, no packets are sent.
+func outboundIP(addr string) (string, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return "", errs.Wrap(err, "dial server")
+	}
+	defer conn.Close()
+
+	local, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return "", errs.Wrap(net.UnknownNetworkError(conn.LocalAddr().Network()), "get local address")
+	}
+	return local.IP.String(), nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 	"github.com/yogenyslav/ya-metrics/pkg/retry"
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -55,7 +56,7 @@ func (a *Agent) sendAllMetrics(ctx context.Context, coll *collector.Collector) e
 	batchCh := make(chan []*model.MetricsDto, batchCount)
 	for range a.cfg.RateLimit {
 		g.Go(func() error {
-			return a.sendMetricsBatch(ctx, batchCh)
+			return a.sendMetricsBatch(ctx, coll, batchCh)
 		})
 	}
 
@@ -72,8 +73,22 @@ func (a *Agent) sendAllMetrics(ctx context.Context, coll *collector.Collector) e
 	return nil
 }
 
-func (a *Agent) sendMetricsBatch(ctx context.Context, batchCh <-chan []*model.MetricsDto) error {
+// sendMetricsBatch sends batches until the channel is closed, counters of every delivered batch
+// are reported to the collector so they are not sent again.
+func (a *Agent) sendMetricsBatch(
+	ctx context.Context,
+	coll *collector.Collector,
+	batchCh <-chan []*model.MetricsDto,
+) error {
 	for batch := range batchCh {
+		if a.grpc != nil {
+			if err := a.sendBatchGRPC(ctx, batch); err != nil {
+				return err
+			}
+			coll.Reported(batch)
+			continue
+		}
+
 		req, err := a.createRequest(ctx, batch)
 		if err != nil {
 			return errs.Wrap(err, "create request")
@@ -107,6 +122,7 @@ func (a *Agent) sendMetricsBatch(ctx context.Context, batchCh <-chan []*model.Me
 			return errs.Wrap(err, "send request")
 		}
 
+		coll.Reported(batch)
 		a.l.Info().Msg("sent metrics batch successfully")
 	}
 
@@ -119,14 +135,31 @@ func (a *Agent) createRequest(ctx context.Context, batch []*model.MetricsDto) (*
 		return nil, errs.Wrap(err, "encode metrics")
 	}
 
+	body := data
+	if a.enc != nil {
+		body, err = a.enc.Encrypt(data)
+		if err != nil {
+			return nil, errs.Wrap(err, "encrypt metrics")
+		}
+	}
+
 	req, err := http.NewRequestWithContext(
-		ctx, http.MethodPost, a.cfg.ServerAddr+"/updates/", bytes.NewReader(data),
+		ctx, http.MethodPost, a.cfg.ServerAddr+"/updates/", bytes.NewReader(body),
 	)
 	if err != nil {
 		return nil, errs.Wrap(err, "create request")
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if a.cfg.InstanceID != "" {
+		req.Header.Set(model.InstanceHeader, a.cfg.InstanceID)
+	}
+	if a.realIP != "" {
+		req.Header.Set(model.RealIPHeader, a.realIP)
+	}
+	if a.enc != nil {
+		req.Header.Set(secure.EncryptionHeader, secure.EncryptionScheme)
+	}
 	if a.cfg.CompressionType != "" {
 		req.Header.Set("Accept-Encoding", a.cfg.CompressionType)
 		req.Header.Set("Content-Encoding", a.cfg.CompressionType)
@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/pkg/retry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// retriableCodes are the gRPC codes of failures which may pass on their own.
+var retriableCodes = map[codes.Code]struct{}{
+	codes.Unavailable:       {},
+	codes.DeadlineExceeded:  {},
+	codes.ResourceExhausted: {},
+	codes.Aborted:           {},
+	codes.Internal:          {},
+}
+
+func (a *Agent) sendBatchGRPC(ctx context.Context, batch []*model.MetricsDto) error {
+	req := &pb.UpdateBatchRequest{Metrics: make([]*pb.Metric, 0, len(batch))}
+	for _, m := range batch {
+		req.Metrics = append(req.Metrics, pb.FromDto(m))
+	}
+
+	if a.cfg.InstanceID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, pb.InstanceMetadata, a.cfg.InstanceID)
+	}
+	if a.realIP != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, pb.RealIPMetadata, a.realIP)
+	}
+	if a.cfg.SecureKey != "" {
+		data, err := pb.SignedPayload(req)
+		if err != nil {
+			return errs.Wrap(err, "encode batch")
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, pb.SignatureMetadata, a.sg.SignatureSHA256(data))
+	}
+
+	err := retry.WithLinearBackoffRetry(ctx, a.cfg.Retry, func(ctx context.Context) error {
+		_, err := a.grpc.UpdateBatch(ctx, req)
+		if err == nil {
+			return nil
+		}
+		if _, ok := retriableCodes[status.Code(err)]; !ok {
+			return errs.Wrap(retry.ErrUnretriable, err.Error())
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, retry.ErrUnretriable) {
+			return errs.Wrap(ErrUpdateMetric, err.Error())
+		}
+		return errs.Wrap(err, "send batch")
+	}
+
+	a.l.Info().Msg("sent metrics batch successfully")
+	return nil
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/agent/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/pkg/retry"
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAgent_sendBatchGRPC(t *testing.T) {
+	t.Parallel()
+
+	batch := []*model.MetricsDto{
+		{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.5)},
+		{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr(int64(3))},
+	}
+	withInstance := mock.MatchedBy(func(ctx context.Context) bool {
+		md, ok := metadata.FromOutgoingContext(ctx)
+		return ok && len(md.Get(pb.InstanceMetadata)) == 1 && md.Get(pb.InstanceMetadata)[0] == "agent-1"
+	})
+	withBatch := mock.MatchedBy(func(req *pb.UpdateBatchRequest) bool {
+		return len(req.GetMetrics()) == 2 &&
+			req.GetMetrics()[0].GetId() == "Alloc" &&
+			req.GetMetrics()[1].GetType() == pb.MetricType_METRIC_TYPE_COUNTER
+	})
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "Sent on first attempt",
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "Unavailable server is retried",
+			errs:      []error{status.Error(codes.Unavailable, "connection refused"), nil},
+			wantCalls: 2,
+		},
+		{
+			name:      "Invalid argument is not retried",
+			errs:      []error{status.Error(codes.InvalidArgument, "no metric id")},
+			wantCalls: 1,
+			wantErr:   ErrUpdateMetric,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := new(mocks.GRPCClient)
+			for _, err := range tt.errs {
+				client.On("UpdateBatch", withInstance, withBatch).Return(&pb.UpdateBatchResponse{}, err).Once()
+			}
+
+			a := New(nil, &config.Config{
+				InstanceID: "agent-1",
+				Retry:      &retry.Config{MaxRetries: 3, LinearBackoffMilli: 1},
+			}, nil, zerolog.Ctx(context.Background()))
+			a.UseGRPC(client)
+
+			err := a.sendBatchGRPC(context.Background(), batch)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			client.AssertNumberOfCalls(t, "UpdateBatch", tt.wantCalls)
+		})
+	}
+}
+
+func TestAgent_sendBatchGRPC_signature(t *testing.T) {
+	t.Parallel()
+
+	const key = "secure_key"
+	sg := secure.NewSignatureGenerator(key)
+	signed := mock.MatchedBy(func(ctx context.Context) bool {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		return len(md.Get(pb.SignatureMetadata)) == 1
+	})
+
+	var signature string
+	client := new(mocks.GRPCClient)
+	client.On("UpdateBatch", signed, mock.Anything).Run(func(args mock.Arguments) {
+		md, _ := metadata.FromOutgoingContext(args.Get(0).(context.Context))
+		signature = md.Get(pb.SignatureMetadata)[0]
+
+		data, err := pb.SignedPayload(args.Get(1).(*pb.UpdateBatchRequest))
+		require.NoError(t, err)
+		assert.Equal(t, sg.SignatureSHA256(data), signature)
+	}).Return(&pb.UpdateBatchResponse{}, nil).Once()
+
+	a := New(nil, &config.Config{
+		SecureKey: key,
+		Retry:     &retry.Config{MaxRetries: 1, LinearBackoffMilli: 1},
+	}, sg, zerolog.Ctx(context.Background()))
+	a.UseGRPC(client)
+
+	batch := []*model.MetricsDto{
+		{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.5), Labels: model.Labels{"b": "2", "a": "1"}},
+	}
+	require.NoError(t, a.sendBatchGRPC(context.Background(), batch))
+	assert.NotEmpty(t, signature)
+}
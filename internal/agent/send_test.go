@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	crand "crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
+	"io"
 	"math/rand/v2"
 	"net/http"
 	"testing"
@@ -16,6 +19,7 @@ import (
 	"github.com/yogenyslav/ya-metrics/internal/agent/collector"
 	"github.com/yogenyslav/ya-metrics/internal/agent/config"
 	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
 	"github.com/yogenyslav/ya-metrics/tests/mocks"
 )
 
@@ -171,7 +175,10 @@ func TestAgent_sendAllMetrics(t *testing.T) {
 
 			pollInterval := 1
 			reportInterval := 1
-			c := collector.NewCollector(pollInterval, zerolog.Ctx(context.Background()))
+			sources, err := collector.NewSources(&config.Config{})
+			require.NoError(t, err)
+			c := collector.NewCollector(pollInterval, zerolog.Ctx(context.Background()), sources...)
+			c.Poll()
 
 			metricsNum := len(c.GetAllMetrics())
 			batchCount := (metricsNum + tt.batchSize - 1) / tt.batchSize
@@ -207,7 +214,7 @@ func TestAgent_sendAllMetrics(t *testing.T) {
 				}, nil).Times(batchCount - successCalls)
 			}
 
-			err := a.sendAllMetrics(ctx, c)
+			err = a.sendAllMetrics(ctx, c)
 			if tt.wantErr {
 				require.Error(t, err)
 				client.AssertNumberOfCalls(t, "Do", successCalls+1)
@@ -218,3 +225,125 @@ func TestAgent_sendAllMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestAgent_createRequest_instance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		instanceID string
+	}{
+		{name: "With instance ID", instanceID: "agent-1"},
+		{name: "Without instance ID", instanceID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := New(http.DefaultClient, &config.Config{
+				ServerAddr: "http://localhost:8080",
+				InstanceID: tt.instanceID,
+			}, nil, zerolog.Ctx(context.Background()))
+
+			req, err := a.createRequest(context.Background(), []*model.MetricsDto{
+				{ID: "PollCount", Type: model.Counter, Delta: new(int64)},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.instanceID, req.Header.Get(model.InstanceHeader))
+		})
+	}
+}
+
+func TestAgent_createRequest_realIP(t *testing.T) {
+	t.Parallel()
+
+	a := New(http.DefaultClient, &config.Config{
+		ServerAddr: "http://127.0.0.1:8080",
+	}, nil, zerolog.Ctx(context.Background()))
+
+	realIP, err := outboundIP(a.serverAddr())
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", realIP)
+	a.realIP = realIP
+
+	req, err := a.createRequest(context.Background(), []*model.MetricsDto{
+		{ID: "PollCount", Type: model.Counter, Delta: new(int64)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", req.Header.Get(model.RealIPHeader))
+}
+
+func TestAgent_createRequest_encryption(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(crand.Reader, 2048)
+	require.NoError(t, err)
+
+	a := New(http.DefaultClient, &config.Config{
+		ServerAddr:      "http://localhost:8080",
+		CompressionType: "gzip",
+	}, nil, zerolog.Ctx(context.Background()))
+	a.UseEncryption(secure.NewEncryptor(&key.PublicKey))
+
+	batch := []*model.MetricsDto{
+		{ID: "PollCount", Type: model.Counter, Delta: new(int64)},
+	}
+	req, err := a.createRequest(context.Background(), batch)
+	require.NoError(t, err)
+	assert.Equal(t, secure.EncryptionScheme, req.Header.Get(secure.EncryptionHeader))
+
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	plain, err := secure.NewDecryptor(key).Decrypt(body)
+	require.NoError(t, err)
+
+	want, err := a.encodeMetrics(batch, "gzip")
+	require.NoError(t, err)
+	assert.Equal(t, want, plain)
+}
+
+func TestAgent_sendAllMetrics_counterIncrements(t *testing.T) {
+	t.Parallel()
+
+	sources, err := collector.NewSources(&config.Config{Collectors: []string{"general"}})
+	require.NoError(t, err)
+	c := collector.NewCollector(1, zerolog.Ctx(context.Background()), sources...)
+
+	var pollCount int64
+	client := new(mocks.HTTPClient)
+	client.On("Do", mock.Anything).Run(func(args mock.Arguments) {
+		var metrics []*model.MetricsDto
+		require.NoError(t, json.NewDecoder(args.Get(0).(*http.Request).Body).Decode(&metrics))
+		for _, m := range metrics {
+			if m.ID == "PollCount" {
+				pollCount += *m.Delta
+			}
+		}
+	}).Return(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil)
+
+	a := New(client, &config.Config{RateLimit: 1, BatchSize: 10}, nil, zerolog.Ctx(context.Background()))
+
+	for range 3 {
+		c.Poll()
+	}
+	require.NoError(t, a.sendAllMetrics(context.Background(), c))
+	assert.Equal(t, int64(3), pollCount)
+
+	for range 2 {
+		c.Poll()
+	}
+	require.NoError(t, a.sendAllMetrics(context.Background(), c))
+	assert.Equal(t, int64(5), pollCount)
+
+	// failed reports keep the counters for the next attempt
+	c.Poll()
+	failing := new(mocks.HTTPClient)
+	failing.On("Do", mock.Anything).Return(&http.Response{StatusCode: http.StatusBadRequest, Body: http.NoBody}, nil)
+	a.client = failing
+	require.Error(t, a.sendAllMetrics(context.Background(), c))
+
+	a.client = client
+	require.NoError(t, a.sendAllMetrics(context.Background(), c))
+	assert.Equal(t, int64(6), pollCount)
+}
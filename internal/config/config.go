@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"net/netip"
 	"os"
+	"strings"
 
+	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 	"github.com/yogenyslav/ya-metrics/pkg/retry"
@@ -12,6 +16,8 @@ import (
 const (
 	defaultServerAddr       string = "localhost:8080"
 	defaultStoreIntervalSec int    = 300
+	defaultAlertIntervalSec int    = 15
+	defaultAlertRepeatSec   int    = 4 * 60 * 60
 )
 
 // DatabaseConfig holds the configuration settings for the database.
@@ -20,10 +26,19 @@ type DatabaseConfig struct {
 }
 
 // ServerConfig holds the configuration settings for the server.
+//
+// Series not updated for StaleAfter seconds are reported as stale, zero disables it.
+// The gRPC API is served on GRPCAddr, empty GRPCAddr disables it.
+// Updates are only accepted from agents in TrustedSubnet, zero TrustedSubnet accepts them from anyone.
+// CryptoKey is the path to the private key decrypting agent payloads.
 type ServerConfig struct {
-	Addr      string
-	LogLevel  string
-	SecureKey string
+	Addr          string
+	GRPCAddr      string
+	LogLevel      string
+	SecureKey     string
+	CryptoKey     string
+	StaleAfter    int
+	TrustedSubnet netip.Prefix
 }
 
 // DumpConfig holds settings for repository dumping into file.
@@ -39,19 +54,52 @@ type AuditConfig struct {
 	URL  string
 }
 
+// HistoryConfig holds settings for time-series history storage.
+//
+// Nil Retention keeps the history forever.
+type HistoryConfig struct {
+	Enabled   bool
+	Retention *model.RetentionPolicy
+}
+
+// AlertConfig holds settings for alerting rules evaluation and notification delivery.
+type AlertConfig struct {
+	RulesFile      string
+	EvalInterval   int
+	WebhookURLs    []string
+	RepeatInterval int
+}
+
+// TLSConfig holds settings for serving HTTPS and gRPC over TLS, empty CertFile disables TLS.
+//
+// Agents have to present a certificate signed by the CA from ClientCAFile unless it is empty.
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+}
+
 // Config holds the entire application settings.
 type Config struct {
-	Server *ServerConfig
-	Dump   *DumpConfig
-	DB     *DatabaseConfig
-	Retry  *retry.Config
-	Audit  *AuditConfig
+	Server  *ServerConfig
+	Dump    *DumpConfig
+	DB      *DatabaseConfig
+	Retry   *retry.Config
+	Audit   *AuditConfig
+	History *HistoryConfig
+	Alert   *AlertConfig
+	TLS     *TLSConfig
 }
 
 // NewConfig creates a new Config with cli args or default values.
 func NewConfig() (*Config, error) {
 	flags := flag.NewFlagSet("server", flag.ExitOnError)
 	addrFlag := flags.String("a", defaultServerAddr, "адрес сервера в формате ip:port")
+	grpcAddrFlag := flags.String(
+		"grpc-address",
+		"",
+		"адрес gRPC сервера в формате ip:port (пустое значение отключает gRPC)",
+	)
 	logLevelFlag := flags.String("l", "debug", "уровень логирования (debug, info, error)")
 	fileStoragePathFlag := flags.String("f", "metrics.json", "путь к файлу для хранения метрик")
 	storeIntervalFlag := flags.Int(
@@ -62,18 +110,80 @@ func NewConfig() (*Config, error) {
 	restoreFlag := flags.Bool("r", false, "восстановление метрик из файла при старте сервера")
 	dbDsnFlag := flags.String("d", "", "строка с адресом подключения к БД")
 	secureKeyFlag := flags.String("k", "", "ключ для подписи сигнатуры сообщений")
+	cryptoKeyFlag := flags.String("crypto-key", "", "путь к файлу с приватным ключом для расшифровки сообщений")
+	trustedSubnetFlag := flags.String("t", "", "доверенная подсеть агентов в формате CIDR")
+	staleAfterFlag := flags.Int(
+		"stale-after",
+		0,
+		"время без обновлений в секундах, после которого метрика считается устаревшей (0 отключает проверку)",
+	)
+	tlsCertFlag := flags.String("tls-cert", "", "путь к файлу с TLS сертификатом сервера")
+	tlsKeyFlag := flags.String("tls-key", "", "путь к файлу с приватным ключом TLS сертификата сервера")
+	tlsClientCAFlag := flags.String(
+		"tls-client-ca",
+		"",
+		"путь к файлу с сертификатом CA для проверки клиентских сертификатов агентов (mTLS)",
+	)
 	auditFileFlag := flags.String("audit-file", "", "путь к файлу аудита")
 	auditURLFlag := flags.String("audit-url", "", "адрес сервиса аудита")
+	historyFlag := flags.Bool("history", false, "хранение истории значений метрик")
+	retentionFlag := flags.String(
+		"retention",
+		"",
+		"политика хранения истории в формате raw:24h,1m:30d,1h:365d (пустое значение хранит историю бессрочно)",
+	)
+	alertRulesFlag := flags.String("alert-rules", "", "путь к JSON файлу с правилами алертинга")
+	alertIntervalFlag := flags.Int(
+		"alert-interval",
+		defaultAlertIntervalSec,
+		"интервал проверки правил алертинга в секундах",
+	)
+	alertWebhooksFlag := flags.String("alert-webhooks", "", "адреса вебхуков для уведомлений об алертах через запятую")
+	alertRepeatFlag := flags.Int(
+		"alert-repeat-interval",
+		defaultAlertRepeatSec,
+		"интервал повторной отправки уведомлений о продолжающихся алертах в секундах",
+	)
 
 	if err := flags.Parse(os.Args[1:]); err != nil {
 		return nil, errs.Wrap(err, "parse flags")
 	}
 
+	retention, err := model.ParseRetentionPolicy(pkg.GetEnv("RETENTION", *retentionFlag))
+	if err != nil {
+		return nil, errs.Wrap(err, "parse retention policy")
+	}
+
+	tlsCfg := &TLSConfig{
+		CertFile:     pkg.GetEnv("TLS_CERT", *tlsCertFlag),
+		KeyFile:      pkg.GetEnv("TLS_KEY", *tlsKeyFlag),
+		ClientCAFile: pkg.GetEnv("TLS_CLIENT_CA", *tlsClientCAFlag),
+	}
+	if (tlsCfg.CertFile == "") != (tlsCfg.KeyFile == "") {
+		return nil, errs.Wrap(errors.New("TLS certificate and key must be set together"), "parse TLS config")
+	}
+	if tlsCfg.CertFile == "" && tlsCfg.ClientCAFile != "" {
+		return nil, errs.Wrap(errors.New("client CA requires TLS certificate"), "parse TLS config")
+	}
+
+	var trustedSubnet netip.Prefix
+	if subnet := pkg.GetEnv("TRUSTED_SUBNET", *trustedSubnetFlag); subnet != "" {
+		trustedSubnet, err = netip.ParsePrefix(subnet)
+		if err != nil {
+			return nil, errs.Wrap(err, "parse trusted subnet")
+		}
+		trustedSubnet = trustedSubnet.Masked()
+	}
+
 	return &Config{
 		Server: &ServerConfig{
-			Addr:      pkg.GetEnv("ADDRESS", *addrFlag),
-			LogLevel:  pkg.GetEnv("LOG_LEVEL", *logLevelFlag),
-			SecureKey: pkg.GetEnv("KEY", *secureKeyFlag),
+			Addr:          pkg.GetEnv("ADDRESS", *addrFlag),
+			GRPCAddr:      pkg.GetEnv("GRPC_ADDRESS", *grpcAddrFlag),
+			LogLevel:      pkg.GetEnv("LOG_LEVEL", *logLevelFlag),
+			SecureKey:     pkg.GetEnv("KEY", *secureKeyFlag),
+			CryptoKey:     pkg.GetEnv("CRYPTO_KEY", *cryptoKeyFlag),
+			StaleAfter:    pkg.GetEnv("STALE_AFTER", *staleAfterFlag),
+			TrustedSubnet: trustedSubnet,
 		},
 		Dump: &DumpConfig{
 			FileStoragePath: pkg.GetEnv("FILE_STORAGE_PATH", *fileStoragePathFlag),
@@ -91,5 +201,27 @@ func NewConfig() (*Config, error) {
 			File: pkg.GetEnv("AUDIT_FILE", *auditFileFlag),
 			URL:  pkg.GetEnv("AUDIT_URL", *auditURLFlag),
 		},
+		History: &HistoryConfig{
+			Enabled:   pkg.GetEnv("HISTORY", *historyFlag),
+			Retention: retention,
+		},
+		Alert: &AlertConfig{
+			RulesFile:      pkg.GetEnv("ALERT_RULES", *alertRulesFlag),
+			EvalInterval:   pkg.GetEnv("ALERT_INTERVAL", *alertIntervalFlag),
+			WebhookURLs:    splitList(pkg.GetEnv("ALERT_WEBHOOKS", *alertWebhooksFlag)),
+			RepeatInterval: pkg.GetEnv("ALERT_REPEAT_INTERVAL", *alertRepeatFlag),
+		},
+		TLS: tlsCfg,
 	}, nil
 }
+
+// splitList splits a comma-separated list skipping empty items.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
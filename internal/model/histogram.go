@@ -0,0 +1,100 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+// HistogramData holds observations of a histogram distributed over buckets.
+//
+// Bounds are strictly increasing upper bounds of the buckets, Counts holds the number of
+// observations per bucket with the last one counting observations above the greatest bound.
+type HistogramData struct {
+	Bounds []float64 `json:"bounds" db:"bounds"`
+	Counts []int64   `json:"counts" db:"counts"`
+	Count  int64     `json:"count"  db:"count"`
+	Sum    float64   `json:"sum"    db:"sum"`
+}
+
+// Validate checks that the histogram buckets and totals are consistent.
+func (h *HistogramData) Validate() error {
+	if len(h.Counts) != len(h.Bounds)+1 {
+		return errors.New("counts must have one more element than bounds")
+	}
+
+	for i, bound := range h.Bounds {
+		if math.IsNaN(bound) || math.IsInf(bound, 0) {
+			return errors.New("bounds must be finite")
+		}
+		if i > 0 && bound <= h.Bounds[i-1] {
+			return errors.New("bounds must be strictly increasing")
+		}
+	}
+
+	var total int64
+	for _, c := range h.Counts {
+		if c < 0 {
+			return errors.New("counts must not be negative")
+		}
+		total += c
+	}
+	if total != h.Count {
+		return errors.New("count must equal the sum of bucket counts")
+	}
+
+	if math.IsNaN(h.Sum) || math.IsInf(h.Sum, 0) {
+		return errors.New("sum must be finite")
+	}
+
+	return nil
+}
+
+// SameBuckets reports whether both histograms have equal bucket bounds.
+func (h *HistogramData) SameBuckets(other *HistogramData) bool {
+	return slices.Equal(h.Bounds, other.Bounds)
+}
+
+// Merge adds observations of other histogram with the same buckets.
+func (h *HistogramData) Merge(other *HistogramData) {
+	for i := range h.Counts {
+		h.Counts[i] += other.Counts[i]
+	}
+	h.Count += other.Count
+	h.Sum += other.Sum
+}
+
+// Clone returns a deep copy of the histogram data.
+func (h *HistogramData) Clone() *HistogramData {
+	return &HistogramData{
+		Bounds: slices.Clone(h.Bounds),
+		Counts: slices.Clone(h.Counts),
+		Count:  h.Count,
+		Sum:    h.Sum,
+	}
+}
+
+// HistogramMetric represents a histogram metric series.
+//
+// UpdatedAt is the time observations were last merged into the series.
+type HistogramMetric struct {
+	ID        string    `db:"id"`
+	Labels    Labels    `db:"labels"`
+	UpdatedAt time.Time `db:"updated_at"`
+	HistogramData
+}
+
+// ToDto converts HistogramMetric to MetricsDto.
+func (m *HistogramMetric) ToDto() *MetricsDto {
+	metric := &MetricsDto{
+		ID:        m.ID,
+		Type:      Histogram,
+		Labels:    m.Labels,
+		Histogram: m.HistogramData.Clone(),
+	}
+	if !m.UpdatedAt.IsZero() {
+		metric.UpdatedAt = &m.UpdatedAt
+	}
+	return metric
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// InstanceLabel is the label identifying the agent which reported a metric.
+	InstanceLabel = "instance"
+	// InstanceHeader is the request header carrying the identity of the agent.
+	InstanceHeader = "X-Instance-ID"
+	// RealIPHeader is the request header carrying the address of the agent on its outbound interface.
+	RealIPHeader = "X-Real-IP"
+)
+
+// Labels is a set of key/value pairs which together with ID identifies a metric series.
+type Labels map[string]string
+
+// Key returns a canonical representation of labels, equal label sets produce equal keys.
+func (l Labels) Key() string {
+	if len(l) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i, name := range slices.Sorted(maps.Keys(l)) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(l[name]))
+	}
+	return b.String()
+}
+
+// Clone returns a copy of labels.
+func (l Labels) Clone() Labels {
+	if len(l) == 0 {
+		return nil
+	}
+	return maps.Clone(l)
+}
+
+// With returns a copy of labels with the label set to value.
+func (l Labels) With(name, value string) Labels {
+	labels := make(Labels, len(l)+1)
+	maps.Copy(labels, l)
+	labels[name] = value
+	return labels
+}
+
+// Valid reports whether all label names match [a-zA-Z_][a-zA-Z0-9_]*.
+func (l Labels) Valid() bool {
+	for name := range l {
+		if !validLabelName(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset drops the label set without touching the map it referenced, since it may be shared.
+func (l *Labels) Reset() {
+	*l = nil
+}
+
+// SeriesKey returns the key identifying a series of the metric with the given ID and labels.
+func SeriesKey(id string, labels Labels) string {
+	if len(labels) == 0 {
+		return id
+	}
+	return id + "{" + labels.Key() + "}"
+}
+
+func validLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -1,18 +1,26 @@
 package model
 
+import "time"
+
 // Metric types.
 const (
-	Counter = "counter"
-	Gauge   = "gauge"
+	Counter   = "counter"
+	Gauge     = "gauge"
+	Histogram = "histogram"
+	Summary   = "summary"
 )
 
 // Metrics represents a metric with its properties.
 //
+// UpdatedAt is the time the series was last reported, repositories set it on every update.
+//
 // generate:reset
 type Metrics[T int64 | float64] struct {
-	ID    string `json:"id"    db:"id"`
-	Type  string `json:"type"  db:"mtype"`
-	Value T      `json:"value" db:"value"`
+	ID        string    `json:"id"         db:"id"`
+	Type      string    `json:"type"       db:"mtype"`
+	Value     T         `json:"value"      db:"value"`
+	Labels    Labels    `json:"labels"     db:"labels"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // NewGaugeMetric creates a new gauge metric.
@@ -42,8 +50,12 @@ func (m *Metrics[T]) GetRecord() (delta *int64, value *float64) {
 // ToDto converts Metrics to MetricsDto.
 func (m *Metrics[T]) ToDto() *MetricsDto {
 	metric := &MetricsDto{
-		ID:   m.ID,
-		Type: m.Type,
+		ID:     m.ID,
+		Type:   m.Type,
+		Labels: m.Labels,
+	}
+	if !m.UpdatedAt.IsZero() {
+		metric.UpdatedAt = &m.UpdatedAt
 	}
 
 	switch v := any(m.Value).(type) {
@@ -58,28 +70,78 @@ func (m *Metrics[T]) ToDto() *MetricsDto {
 
 // MetricsDto is a struct for transferring metric data.
 //
+// UpdatedAt and Stale are only set by the server, Stale marks series not updated for too long.
+//
 // generate:reset
 type MetricsDto struct {
-	ID    string   `json:"id"              db:"id"`
-	Type  string   `json:"type"            db:"mtype"`
-	Value *float64 `json:"value,omitempty" db:"value"`
-	Delta *int64   `json:"delta,omitempty" db:"delta"`
+	ID        string         `json:"id"                   db:"id"`
+	Type      string         `json:"type"                 db:"mtype"`
+	Value     *float64       `json:"value,omitempty"      db:"value"`
+	Delta     *int64         `json:"delta,omitempty"      db:"delta"`
+	Labels    Labels         `json:"labels,omitempty"     db:"labels"`
+	Histogram *HistogramData `json:"histogram,omitempty"  db:"-"`
+	Summary   *SummaryData   `json:"summary,omitempty"    db:"-"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty" db:"updated_at"`
+	Stale     bool           `json:"stale,omitempty"      db:"-"`
+}
+
+// ToHistogramMetric converts MetricsDto to a HistogramMetric.
+func (m *MetricsDto) ToHistogramMetric() *HistogramMetric {
+	metric := &HistogramMetric{
+		ID:            m.ID,
+		Labels:        m.Labels,
+		HistogramData: *m.Histogram.Clone(),
+	}
+	if m.UpdatedAt != nil {
+		metric.UpdatedAt = *m.UpdatedAt
+	}
+	return metric
+}
+
+// ToSummaryMetric converts a persisted MetricsDto to a SummaryMetric.
+func (m *MetricsDto) ToSummaryMetric() *SummaryMetric {
+	metric := &SummaryMetric{
+		ID:     m.ID,
+		Labels: m.Labels,
+		Sketch: m.Summary.Sketch.Clone(),
+	}
+	if m.UpdatedAt != nil {
+		metric.UpdatedAt = *m.UpdatedAt
+	}
+	return metric
+}
+
+// MetricGroup is a struct for transferring all series of a metric.
+type MetricGroup struct {
+	ID     string        `json:"id"`
+	Type   string        `json:"type"`
+	Series []*MetricsDto `json:"series"`
 }
 
 // ToGaugeMetric converts MetricsDto to a Gauge Metrics.
 func (m *MetricsDto) ToGaugeMetric() *Metrics[float64] {
-	return &Metrics[float64]{
-		ID:    m.ID,
-		Type:  Gauge,
-		Value: *m.Value,
+	metric := &Metrics[float64]{
+		ID:     m.ID,
+		Type:   Gauge,
+		Value:  *m.Value,
+		Labels: m.Labels,
 	}
+	if m.UpdatedAt != nil {
+		metric.UpdatedAt = *m.UpdatedAt
+	}
+	return metric
 }
 
 // ToCounterMetric converts MetricsDto to a Counter Metrics.
 func (m *MetricsDto) ToCounterMetric() *Metrics[int64] {
-	return &Metrics[int64]{
-		ID:    m.ID,
-		Type:  Counter,
-		Value: *m.Delta,
+	metric := &Metrics[int64]{
+		ID:     m.ID,
+		Type:   Counter,
+		Value:  *m.Delta,
+		Labels: m.Labels,
 	}
+	if m.UpdatedAt != nil {
+		metric.UpdatedAt = *m.UpdatedAt
+	}
+	return metric
 }
@@ -2,10 +2,14 @@
 
 package model
 
+import "time"
+
 func (x *Metrics[T]) Reset() {
 	x.ID = ""
 	x.Type = ""
 	x.Value = *new(T)
+	x.Labels.Reset()
+	x.UpdatedAt = time.Time{}
 }
 
 func (x *MetricsDto) Reset() {
@@ -13,5 +17,10 @@ func (x *MetricsDto) Reset() {
 	x.Type = ""
 	x.Value = nil
 	x.Delta = nil
+	x.Labels.Reset()
+	x.Histogram = nil
+	x.Summary = nil
+	x.UpdatedAt = nil
+	x.Stale = false
 }
 
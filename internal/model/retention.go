@@ -0,0 +1,144 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RawResolution names raw samples in a retention policy.
+const RawResolution = "raw"
+
+// RollupTier describes downsampled history kept at a fixed resolution.
+type RollupTier struct {
+	Resolution time.Duration
+	Keep       time.Duration
+}
+
+// RetentionPolicy describes how long raw samples and their rollups are kept.
+//
+// Zero Raw keeps raw samples forever, tiers are ordered by resolution.
+type RetentionPolicy struct {
+	Raw   time.Duration
+	Tiers []RollupTier
+}
+
+// ParseRetentionPolicy parses a policy like "raw:24h,1m:30d,1h:365d".
+//
+// Every item is a resolution followed by the time to keep data at it, an empty string means no policy.
+func ParseRetentionPolicy(s string) (*RetentionPolicy, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	policy := &RetentionPolicy{}
+	for item := range strings.SplitSeq(s, ",") {
+		resolution, keep, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			return nil, fmt.Errorf("retention item %q must be in resolution:keep format", item)
+		}
+
+		keepDur, err := parseRetentionDuration(keep)
+		if err != nil {
+			return nil, err
+		}
+
+		if resolution == RawResolution {
+			policy.Raw = keepDur
+			continue
+		}
+
+		resolutionDur, err := parseRetentionDuration(resolution)
+		if err != nil {
+			return nil, err
+		}
+		policy.Tiers = append(policy.Tiers, RollupTier{Resolution: resolutionDur, Keep: keepDur})
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Validate checks that every tier can be computed from the data kept by the previous one.
+func (p *RetentionPolicy) Validate() error {
+	sourceKeep := p.Raw
+	var sourceResolution time.Duration
+
+	for _, tier := range p.Tiers {
+		if tier.Resolution <= sourceResolution {
+			return errors.New("rollup resolutions must be strictly increasing")
+		}
+		if sourceResolution > 0 && tier.Resolution%sourceResolution != 0 {
+			return fmt.Errorf("rollup resolution %s is not a multiple of %s", tier.Resolution, sourceResolution)
+		}
+		if sourceKeep > 0 && sourceKeep < tier.Resolution {
+			return fmt.Errorf("data is dropped before it is rolled up into %s", tier.Resolution)
+		}
+		if tier.Keep < tier.Resolution {
+			return fmt.Errorf("rollups of %s must be kept at least for their resolution", tier.Resolution)
+		}
+
+		sourceResolution = tier.Resolution
+		sourceKeep = tier.Keep
+	}
+
+	return nil
+}
+
+// TierFor returns the finest tier still holding data recorded at since, or the coarsest tier if none does.
+func (p *RetentionPolicy) TierFor(since, now time.Time) (RollupTier, bool) {
+	if len(p.Tiers) == 0 {
+		return RollupTier{}, false
+	}
+
+	for _, tier := range p.Tiers {
+		if !since.Before(now.Add(-tier.Keep)) {
+			return tier, true
+		}
+	}
+	return p.Tiers[len(p.Tiers)-1], true
+}
+
+// parseRetentionDuration extends time.ParseDuration with days, e.g. "30d".
+func parseRetentionDuration(s string) (time.Duration, error) {
+	var (
+		d   time.Duration
+		err error
+	)
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int64
+		n, err = strconv.ParseInt(days, 10, 64)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(s)
+	}
+
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid retention duration %q", s)
+	}
+	return d, nil
+}
+
+// Rollup aggregates samples of a metric series recorded during one resolution interval starting at Timestamp.
+//
+// Gauges fill Min, Max and Avg, counters fill Sum and Rate, the increase per second, Last is set for both.
+type Rollup struct {
+	ID         string    `json:"id"         db:"id"`
+	Type       string    `json:"type"       db:"mtype"`
+	Labels     Labels    `json:"labels"     db:"labels"`
+	Resolution int64     `json:"resolution" db:"resolution"`
+	Timestamp  time.Time `json:"ts"         db:"ts"`
+	Count      int64     `json:"count"      db:"count"`
+	Min        float64   `json:"min"        db:"min"`
+	Max        float64   `json:"max"        db:"max"`
+	Avg        float64   `json:"avg"        db:"avg"`
+	Last       float64   `json:"last"       db:"last"`
+	Sum        float64   `json:"sum"        db:"sum"`
+	Rate       float64   `json:"rate"       db:"rate"`
+}
@@ -0,0 +1,79 @@
+package model
+
+import "time"
+
+// Sample is a single timestamped value of a metric series.
+type Sample[T int64 | float64] struct {
+	ID        string    `json:"id"     db:"id"`
+	Type      string    `json:"type"   db:"mtype"`
+	Labels    Labels    `json:"labels" db:"labels"`
+	Value     T         `json:"value"  db:"value"`
+	Timestamp time.Time `json:"ts"     db:"ts"`
+}
+
+// NewSample creates a sample from the metric value observed at ts.
+func NewSample[T int64 | float64](m *Metrics[T], ts time.Time) *Sample[T] {
+	return &Sample[T]{
+		ID:        m.ID,
+		Type:      m.Type,
+		Labels:    m.Labels,
+		Value:     m.Value,
+		Timestamp: ts,
+	}
+}
+
+// ToPoint converts Sample to Point.
+func (s *Sample[T]) ToPoint() *Point {
+	point := &Point{
+		Timestamp: s.Timestamp.Unix(),
+	}
+
+	switch v := any(s.Value).(type) {
+	case int64:
+		point.Delta = &v
+	case float64:
+		point.Value = &v
+	}
+
+	return point
+}
+
+// Point is a struct for transferring a single value of a metric series.
+type Point struct {
+	Timestamp int64    `json:"ts"`
+	Value     *float64 `json:"value,omitempty"`
+	Delta     *int64   `json:"delta,omitempty"`
+}
+
+// Functions applied to counter values in a range query.
+const (
+	FuncRate     = "rate"
+	FuncIncrease = "increase"
+)
+
+// RangeQuery is a request for metric values sampled at a fixed step.
+//
+// Start, End, Step and Window are expressed in seconds, Start and End being Unix timestamps.
+// Func computes the counter rate or increase over Window preceding every step, Window defaults to Step.
+type RangeQuery struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Labels Labels `json:"labels,omitempty"`
+	Start  int64  `json:"start"`
+	End    int64  `json:"end"`
+	Step   int64  `json:"step"`
+	Func   string `json:"func,omitempty"`
+	Window int64  `json:"window,omitempty"`
+}
+
+// Series is a struct for transferring points of a metric series.
+//
+// Resolution is set in seconds when points are served from rollups.
+type Series struct {
+	ID         string   `json:"id"`
+	Type       string   `json:"type"`
+	Labels     Labels   `json:"labels,omitempty"`
+	Func       string   `json:"func,omitempty"`
+	Resolution int64    `json:"resolution,omitempty"`
+	Points     []*Point `json:"points"`
+}
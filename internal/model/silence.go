@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Silence suppresses notifications of alerts matching it between StartsAt and EndsAt.
+//
+// Empty MetricID matches alerts of any metric, an alert must have all of the Labels to match.
+type Silence struct {
+	ID        string    `json:"id"         db:"id"`
+	MetricID  string    `json:"metric"     db:"metric_id"`
+	Labels    Labels    `json:"labels"     db:"labels"`
+	StartsAt  time.Time `json:"starts_at"  db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"    db:"ends_at"`
+	Comment   string    `json:"comment"    db:"comment"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Validate checks that the silence has a matcher and a valid time range.
+func (s *Silence) Validate() error {
+	if s.MetricID == "" && len(s.Labels) == 0 {
+		return errors.New("silence matches every alert")
+	}
+	if !s.Labels.Valid() {
+		return errors.New("invalid silence labels")
+	}
+	if s.StartsAt.IsZero() || s.EndsAt.IsZero() || !s.EndsAt.After(s.StartsAt) {
+		return errors.New("silence must end after it starts")
+	}
+	return nil
+}
+
+// Active reports whether the silence is in effect at the given time.
+func (s *Silence) Active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Matches reports whether the silence matches a series of the metric with the given ID and labels.
+func (s *Silence) Matches(metricID string, labels Labels) bool {
+	if s.MetricID != "" && s.MetricID != metricID {
+		return false
+	}
+	for name, value := range s.Labels {
+		if v, ok := labels[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
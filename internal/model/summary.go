@@ -0,0 +1,71 @@
+package model
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/pkg/sketch"
+)
+
+// SummaryQuantiles are the quantiles reported for summary metrics.
+var SummaryQuantiles = []float64{0.5, 0.9, 0.99}
+
+// SummaryData is a struct for transferring the state of a summary metric.
+//
+// Sketch is only filled when the summary is persisted, clients receive the estimated quantiles.
+type SummaryData struct {
+	Count     int64              `json:"count"`
+	Sum       float64            `json:"sum"`
+	Quantiles map[string]float64 `json:"quantiles,omitempty"`
+	Sketch    *sketch.Sketch     `json:"sketch,omitempty"`
+}
+
+// SummaryMetric represents a summary metric series.
+//
+// UpdatedAt is the time observations were last merged into the series.
+type SummaryMetric struct {
+	ID        string         `db:"id"`
+	Labels    Labels         `db:"labels"`
+	Sketch    *sketch.Sketch `db:"sketch"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+// NewSummaryMetric creates a summary metric series with observed values.
+func NewSummaryMetric(id string, labels Labels, values ...float64) *SummaryMetric {
+	s := sketch.New(sketch.DefaultRelativeAccuracy)
+	for _, v := range values {
+		s.Add(v)
+	}
+
+	return &SummaryMetric{
+		ID:     id,
+		Labels: labels,
+		Sketch: s,
+	}
+}
+
+// ToDto converts SummaryMetric to MetricsDto.
+func (m *SummaryMetric) ToDto() *MetricsDto {
+	quantiles := make(map[string]float64, len(SummaryQuantiles))
+	for _, q := range SummaryQuantiles {
+		if v := m.Sketch.Quantile(q); !math.IsNaN(v) {
+			quantiles[strconv.FormatFloat(q, 'g', -1, 64)] = v
+		}
+	}
+
+	metric := &MetricsDto{
+		ID:     m.ID,
+		Type:   Summary,
+		Labels: m.Labels,
+		Summary: &SummaryData{
+			Count:     m.Sketch.Count,
+			Sum:       m.Sketch.Sum,
+			Quantiles: quantiles,
+		},
+	}
+	if !m.UpdatedAt.IsZero() {
+		metric.UpdatedAt = &m.UpdatedAt
+	}
+	return metric
+}
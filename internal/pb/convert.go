@@ -0,0 +1,105 @@
+// Package pb holds the gRPC API of the server generated from metrics.proto and conversions to the model.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative metrics.proto
+
+import (
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// InstanceMetadata is the metadata key carrying the identity of the agent, like model.InstanceHeader in HTTP.
+	InstanceMetadata = "x-instance-id"
+	// RealIPMetadata is the metadata key carrying the address of the agent, like model.RealIPHeader in HTTP.
+	RealIPMetadata = "x-real-ip"
+	// SignatureMetadata is the metadata key carrying the signature of the request, like HashSHA256 in HTTP.
+	SignatureMetadata = "hashsha256"
+)
+
+var (
+	metricTypes = map[MetricType]string{
+		MetricType_METRIC_TYPE_GAUGE:     model.Gauge,
+		MetricType_METRIC_TYPE_COUNTER:   model.Counter,
+		MetricType_METRIC_TYPE_HISTOGRAM: model.Histogram,
+		MetricType_METRIC_TYPE_SUMMARY:   model.Summary,
+	}
+	protoTypes = map[string]MetricType{
+		model.Gauge:     MetricType_METRIC_TYPE_GAUGE,
+		model.Counter:   MetricType_METRIC_TYPE_COUNTER,
+		model.Histogram: MetricType_METRIC_TYPE_HISTOGRAM,
+		model.Summary:   MetricType_METRIC_TYPE_SUMMARY,
+	}
+)
+
+// SignedPayload returns the encoding of the message covered by its signature, map entries are sorted by key
+// so that the agent and the server encode the same message to the same bytes.
+func SignedPayload(m proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+}
+
+// ModelType returns the model name of the metric type, unspecified type has an empty name.
+func ModelType(t MetricType) string {
+	return metricTypes[t]
+}
+
+// ProtoType returns the metric type by its model name.
+func ProtoType(t string) MetricType {
+	return protoTypes[t]
+}
+
+// FromDto converts a model metric to its protobuf representation.
+func FromDto(m *model.MetricsDto) *Metric {
+	metric := &Metric{
+		Id:     m.ID,
+		Type:   ProtoType(m.Type),
+		Value:  m.Value,
+		Delta:  m.Delta,
+		Labels: m.Labels,
+		Stale:  m.Stale,
+	}
+
+	if m.Histogram != nil {
+		metric.Histogram = &Histogram{
+			Bounds: m.Histogram.Bounds,
+			Counts: m.Histogram.Counts,
+			Count:  m.Histogram.Count,
+			Sum:    m.Histogram.Sum,
+		}
+	}
+	if m.Summary != nil {
+		metric.Summary = &Summary{
+			Count:     m.Summary.Count,
+			Sum:       m.Summary.Sum,
+			Quantiles: m.Summary.Quantiles,
+		}
+	}
+	if m.UpdatedAt != nil {
+		metric.UpdatedAt = timestamppb.New(*m.UpdatedAt)
+	}
+
+	return metric
+}
+
+// ToDto converts a received metric to the model, server-side fields are not taken.
+func ToDto(m *Metric) *model.MetricsDto {
+	metric := &model.MetricsDto{
+		ID:     m.GetId(),
+		Type:   ModelType(m.GetType()),
+		Value:  m.Value,
+		Delta:  m.Delta,
+		Labels: m.GetLabels(),
+	}
+
+	if h := m.GetHistogram(); h != nil {
+		metric.Histogram = &model.HistogramData{
+			Bounds: h.GetBounds(),
+			Counts: h.GetCounts(),
+			Count:  h.GetCount(),
+			Sum:    h.GetSum(),
+		}
+	}
+
+	return metric
+}
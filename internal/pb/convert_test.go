@@ -0,0 +1,131 @@
+package pb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg"
+)
+
+func TestFromDto(t *testing.T) {
+	t.Parallel()
+
+	updatedAt := time.Unix(1000, 0).UTC()
+
+	tests := []struct {
+		name   string
+		metric *model.MetricsDto
+		check  func(t *testing.T, m *Metric)
+	}{
+		{
+			name: "Gauge with labels",
+			metric: &model.MetricsDto{
+				ID:        "Alloc",
+				Type:      model.Gauge,
+				Value:     pkg.Ptr(1.5),
+				Labels:    model.Labels{"host": "a"},
+				UpdatedAt: &updatedAt,
+				Stale:     true,
+			},
+			check: func(t *testing.T, m *Metric) {
+				assert.Equal(t, MetricType_METRIC_TYPE_GAUGE, m.GetType())
+				assert.InDelta(t, 1.5, m.GetValue(), 0)
+				assert.Equal(t, map[string]string{"host": "a"}, m.GetLabels())
+				assert.Equal(t, updatedAt, m.GetUpdatedAt().AsTime())
+				assert.True(t, m.GetStale())
+			},
+		},
+		{
+			name: "Histogram",
+			metric: &model.MetricsDto{
+				ID:   "latency",
+				Type: model.Histogram,
+				Histogram: &model.HistogramData{
+					Bounds: []float64{0.1, 1},
+					Counts: []int64{1, 2, 0},
+					Count:  3,
+					Sum:    1.2,
+				},
+			},
+			check: func(t *testing.T, m *Metric) {
+				assert.Equal(t, MetricType_METRIC_TYPE_HISTOGRAM, m.GetType())
+				assert.Equal(t, []int64{1, 2, 0}, m.GetHistogram().GetCounts())
+				assert.Nil(t, m.GetUpdatedAt())
+			},
+		},
+		{
+			name: "Summary",
+			metric: &model.MetricsDto{
+				ID:   "duration",
+				Type: model.Summary,
+				Summary: &model.SummaryData{
+					Count:     2,
+					Sum:       3,
+					Quantiles: map[string]float64{"0.5": 1},
+				},
+			},
+			check: func(t *testing.T, m *Metric) {
+				assert.Equal(t, MetricType_METRIC_TYPE_SUMMARY, m.GetType())
+				assert.Equal(t, int64(2), m.GetSummary().GetCount())
+				assert.Equal(t, map[string]float64{"0.5": 1}, m.GetSummary().GetQuantiles())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := FromDto(tt.metric)
+			assert.Equal(t, tt.metric.ID, m.GetId())
+			tt.check(t, m)
+		})
+	}
+}
+
+func TestToDto(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		metric *Metric
+		want   *model.MetricsDto
+	}{
+		{
+			name:   "Counter",
+			metric: &Metric{Id: "PollCount", Type: MetricType_METRIC_TYPE_COUNTER, Delta: pkg.Ptr(int64(3))},
+			want:   &model.MetricsDto{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr(int64(3))},
+		},
+		{
+			name: "Server-side fields are ignored",
+			metric: &Metric{
+				Id:     "Alloc",
+				Type:   MetricType_METRIC_TYPE_GAUGE,
+				Value:  pkg.Ptr(1.5),
+				Labels: map[string]string{"host": "a"},
+				Stale:  true,
+			},
+			want: &model.MetricsDto{
+				ID:     "Alloc",
+				Type:   model.Gauge,
+				Value:  pkg.Ptr(1.5),
+				Labels: model.Labels{"host": "a"},
+			},
+		},
+		{
+			name:   "Unspecified type",
+			metric: &Metric{Id: "Alloc"},
+			want:   &model.MetricsDto{ID: "Alloc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, ToDto(tt.metric))
+		})
+	}
+}
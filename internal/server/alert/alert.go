@@ -0,0 +1,45 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+// State is the state of an alert.
+type State string
+
+// Alert states.
+const (
+	StatePending  State = "pending"
+	StateFiring   State = "firing"
+	StateResolved State = "resolved"
+)
+
+// Alert is the state of a rule evaluated against one metric series.
+//
+// Score is the deviation of the value from the series history in standard deviations, set by anomaly rules.
+type Alert struct {
+	Rule       string       `json:"rule"`
+	Group      string       `json:"group"`
+	MetricID   string       `json:"metric"`
+	Type       string       `json:"type"`
+	Labels     model.Labels `json:"labels,omitempty"`
+	State      State        `json:"state"`
+	Value      float64      `json:"value"`
+	Score      float64      `json:"score,omitempty"`
+	ActiveAt   time.Time    `json:"active_at"`
+	FiredAt    *time.Time   `json:"fired_at,omitempty"`
+	ResolvedAt *time.Time   `json:"resolved_at,omitempty"`
+	SilencedBy []string     `json:"silenced_by,omitempty"`
+}
+
+// Silenced reports whether notifications of the alert are suppressed by an active silence.
+func (a *Alert) Silenced() bool {
+	return len(a.SilencedBy) > 0
+}
+
+// Key identifies the alert among alerts of all rules.
+func (a *Alert) Key() string {
+	return a.Rule + "/" + model.SeriesKey(a.MetricID, a.Labels)
+}
@@ -0,0 +1,164 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Anomaly detection methods.
+const (
+	// MethodZScore compares a value with the mean and deviation of the last Window values of the series.
+	MethodZScore = "zscore"
+	// MethodEWMA compares a value with the exponentially weighted moving average and deviation of the series.
+	MethodEWMA = "ewma"
+)
+
+// Anomaly describes a condition holding when a value deviates from the recent history of its series
+// by more than Deviations standard deviations.
+//
+// No decision is made until the series has MinSamples values, by default the window size for zscore
+// and 1/Alpha for ewma, or while its history has no deviation at all.
+type Anomaly struct {
+	Method     string  `json:"method"`
+	Deviations float64 `json:"deviations"`
+	Window     int     `json:"window,omitempty"`
+	Alpha      float64 `json:"alpha,omitempty"`
+	MinSamples int     `json:"min_samples,omitempty"`
+}
+
+// Validate checks that the parameters of the method are set.
+func (a *Anomaly) Validate() error {
+	if a.Deviations <= 0 {
+		return errors.New("anomaly deviations must be positive")
+	}
+	if a.MinSamples < 0 {
+		return errors.New("anomaly min samples must not be negative")
+	}
+
+	switch a.Method {
+	case MethodZScore:
+		if a.Window < 2 {
+			return errors.New("zscore window must hold at least 2 values")
+		}
+	case MethodEWMA:
+		if a.Alpha <= 0 || a.Alpha >= 1 {
+			return errors.New("ewma alpha must be in (0, 1)")
+		}
+	default:
+		return fmt.Errorf("unsupported anomaly method %q", a.Method)
+	}
+	return nil
+}
+
+func (a *Anomaly) minSamples() int {
+	switch {
+	case a.MinSamples > 0:
+		return a.MinSamples
+	case a.Method == MethodZScore:
+		return a.Window
+	default:
+		return int(math.Ceil(1 / a.Alpha))
+	}
+}
+
+// rollingStats is the history of a series summarized by its mean and standard deviation.
+type rollingStats interface {
+	add(value float64)
+	count() int
+	mean() float64
+	stdDev() float64
+}
+
+func (a *Anomaly) newStats() rollingStats {
+	if a.Method == MethodZScore {
+		return &windowStats{values: make([]float64, 0, a.Window), size: a.Window}
+	}
+	return &ewmaStats{alpha: a.Alpha}
+}
+
+// score returns the deviation of the value from the series history in standard deviations
+// and reports whether it can be judged, the value is added to the history afterwards.
+func (a *Anomaly) score(stats rollingStats, value float64) (float64, bool) {
+	defer stats.add(value)
+
+	if stats.count() < a.minSamples() {
+		return 0, false
+	}
+
+	sd := stats.stdDev()
+	if sd == 0 {
+		return 0, false
+	}
+	return (value - stats.mean()) / sd, true
+}
+
+// windowStats keeps the last size values in a ring buffer.
+type windowStats struct {
+	values []float64
+	size   int
+	next   int
+}
+
+func (s *windowStats) add(value float64) {
+	if len(s.values) < s.size {
+		s.values = append(s.values, value)
+		return
+	}
+	s.values[s.next] = value
+	s.next = (s.next + 1) % s.size
+}
+
+func (s *windowStats) count() int {
+	return len(s.values)
+}
+
+func (s *windowStats) mean() float64 {
+	var sum float64
+	for _, v := range s.values {
+		sum += v
+	}
+	return sum / float64(len(s.values))
+}
+
+func (s *windowStats) stdDev() float64 {
+	mean := s.mean()
+	var sum float64
+	for _, v := range s.values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(s.values)))
+}
+
+// ewmaStats keeps exponentially weighted moving average and variance.
+type ewmaStats struct {
+	alpha    float64
+	avg      float64
+	variance float64
+	n        int
+}
+
+func (s *ewmaStats) add(value float64) {
+	s.n++
+	if s.n == 1 {
+		s.avg = value
+		return
+	}
+
+	diff := value - s.avg
+	incr := s.alpha * diff
+	s.avg += incr
+	s.variance = (1 - s.alpha) * (s.variance + diff*incr)
+}
+
+func (s *ewmaStats) count() int {
+	return s.n
+}
+
+func (s *ewmaStats) mean() float64 {
+	return s.avg
+}
+
+func (s *ewmaStats) stdDev() float64 {
+	return math.Sqrt(s.variance)
+}
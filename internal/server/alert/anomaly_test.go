@@ -0,0 +1,103 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnomaly_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		anomaly Anomaly
+		wantErr bool
+	}{
+		{name: "Valid zscore", anomaly: Anomaly{Method: MethodZScore, Deviations: 3, Window: 10}},
+		{name: "Valid ewma", anomaly: Anomaly{Method: MethodEWMA, Deviations: 3, Alpha: 0.1}},
+		{name: "No deviations", anomaly: Anomaly{Method: MethodZScore, Window: 10}, wantErr: true},
+		{name: "Short window", anomaly: Anomaly{Method: MethodZScore, Deviations: 3, Window: 1}, wantErr: true},
+		{name: "Alpha out of range", anomaly: Anomaly{Method: MethodEWMA, Deviations: 3, Alpha: 1}, wantErr: true},
+		{name: "Unknown method", anomaly: Anomaly{Method: "mad", Deviations: 3}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.anomaly.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAnomaly_score(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		anomaly   Anomaly
+		history   []float64
+		value     float64
+		wantScore float64
+		wantOk    bool
+	}{
+		{
+			name:      "Zscore over the window",
+			anomaly:   Anomaly{Method: MethodZScore, Deviations: 3, Window: 4},
+			history:   []float64{100, 2, 4, 2, 4},
+			value:     9,
+			wantScore: 6,
+			wantOk:    true,
+		},
+		{
+			name:    "Zscore before window is filled",
+			anomaly: Anomaly{Method: MethodZScore, Deviations: 3, Window: 4},
+			history: []float64{2, 4, 2},
+			value:   9,
+		},
+		{
+			name:      "Zscore with min samples",
+			anomaly:   Anomaly{Method: MethodZScore, Deviations: 3, Window: 4, MinSamples: 2},
+			history:   []float64{2, 4},
+			value:     0,
+			wantScore: -3,
+			wantOk:    true,
+		},
+		{
+			name:    "Flat history",
+			anomaly: Anomaly{Method: MethodZScore, Deviations: 3, Window: 2},
+			history: []float64{5, 5},
+			value:   6,
+		},
+		{
+			name:      "Ewma",
+			anomaly:   Anomaly{Method: MethodEWMA, Deviations: 3, Alpha: 0.5},
+			history:   []float64{2, 4},
+			value:     4,
+			wantScore: 1,
+			wantOk:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stats := tt.anomaly.newStats()
+			for _, v := range tt.history {
+				stats.add(v)
+			}
+
+			score, ok := tt.anomaly.score(stats, tt.value)
+			require.Equal(t, tt.wantOk, ok)
+			assert.InDelta(t, tt.wantScore, score, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package alert
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// resolvedRetention is how long resolved alerts are still reported.
+const resolvedRetention = 15 * time.Minute
+
+type metricLister interface {
+	ListMetrics(ctx context.Context) ([]*model.MetricsDto, error)
+}
+
+type silenceRepo interface {
+	Create(ctx context.Context, silence model.Silence) error
+	List(ctx context.Context) ([]model.Silence, error)
+	Expire(ctx context.Context, id string, now time.Time) error
+}
+
+type notifier interface {
+	Notify(ctx context.Context, now time.Time, alerts []Alert) error
+}
+
+// Engine evaluates rules against current metric values and keeps the alerts state in memory.
+type Engine struct {
+	rules    []Rule
+	metrics  metricLister
+	silences silenceRepo
+	notifier notifier
+	alerts   map[string]*Alert
+	stats    map[string]*seriesStats
+	mu       *sync.RWMutex
+}
+
+// New creates a new Engine instance, nil notifier disables notifications.
+func New(rules []Rule, metrics metricLister, silences silenceRepo, notifier notifier) *Engine {
+	return &Engine{
+		rules:    rules,
+		metrics:  metrics,
+		silences: silences,
+		notifier: notifier,
+		alerts:   make(map[string]*Alert),
+		stats:    make(map[string]*seriesStats),
+		mu:       &sync.RWMutex{},
+	}
+}
+
+// Evaluate checks every rule against the current metric values.
+//
+// A series satisfying a rule makes its alert pending and firing once the condition holds for the rule duration,
+// a firing alert is resolved as soon as the condition stops holding and a pending one is dropped.
+// Anomaly rules keep the history of every series they select, series gone from the metrics lose it.
+// A value is added to the history only once, series not updated since the previous evaluation keep their decision.
+// Absent rules hold for series not updated for too long.
+// Alerts matching an active silence are marked silenced, then the resulting alerts are passed to the notifier.
+func (e *Engine) Evaluate(ctx context.Context, now time.Time) error {
+	if len(e.rules) == 0 {
+		return nil
+	}
+
+	metrics, err := e.metrics.ListMetrics(ctx)
+	if err != nil {
+		return errs.Wrap(err, "list metrics")
+	}
+
+	silences, err := e.silences.List(ctx)
+	if err != nil {
+		return errs.Wrap(err, "list silences")
+	}
+
+	e.update(metrics, now)
+	e.silence(silences, now)
+
+	if e.notifier == nil {
+		return nil
+	}
+	return errs.Wrap(e.notifier.Notify(ctx, now, e.Alerts()), "notify alerts")
+}
+
+func (e *Engine) update(metrics []*model.MetricsDto, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	active := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	for i := range e.rules {
+		rule := &e.rules[i]
+		if rule.Absent > 0 {
+			e.updateAbsent(rule, metrics, now, active)
+			continue
+		}
+
+		for _, m := range metrics {
+			if !rule.Selects(m) {
+				continue
+			}
+
+			value, ok := metricValue(m)
+			if !ok {
+				continue
+			}
+
+			var score float64
+			if rule.Anomaly != nil {
+				key := rule.Name + "/" + model.SeriesKey(m.ID, m.Labels)
+				seen[key] = struct{}{}
+				if score, ok = e.anomalyScore(key, rule.Anomaly, m, value); !ok {
+					continue
+				}
+			} else if !rule.Holds(value) {
+				continue
+			}
+
+			a := e.activate(rule, m, value, now)
+			a.Score = score
+			active[a.Key()] = struct{}{}
+		}
+	}
+
+	for key, a := range e.alerts {
+		if _, ok := active[key]; !ok {
+			e.deactivate(key, a, now)
+		}
+	}
+
+	for key := range e.stats {
+		if _, ok := seen[key]; !ok {
+			delete(e.stats, key)
+		}
+	}
+}
+
+// updateAbsent activates alerts of series not updated for the rule duration, their value is the time since update
+// in seconds. The metric having no series at all raises a single alert labeled as the rule selector.
+func (e *Engine) updateAbsent(rule *Rule, metrics []*model.MetricsDto, now time.Time, active map[string]struct{}) {
+	selected := false
+	for _, m := range metrics {
+		if !rule.Selects(m) {
+			continue
+		}
+		selected = true
+
+		if m.UpdatedAt == nil {
+			continue
+		}
+		if age := now.Sub(*m.UpdatedAt); age > time.Duration(rule.Absent) {
+			a := e.activate(rule, m, age.Seconds(), now)
+			active[a.Key()] = struct{}{}
+		}
+	}
+
+	if !selected {
+		a := e.activate(rule, &model.MetricsDto{ID: rule.MetricID, Type: rule.Type, Labels: rule.Labels}, 0, now)
+		active[a.Key()] = struct{}{}
+	}
+}
+
+// seriesStats is the history of a series with the decision made on its last update.
+type seriesStats struct {
+	rollingStats
+	updatedAt time.Time
+	score     float64
+	anomalous bool
+}
+
+// anomalyScore reports the score of the value if it deviates from the series history more than the rule allows.
+// A value not updated since the previous call is not added to the history again, the previous decision is kept.
+func (e *Engine) anomalyScore(key string, anomaly *Anomaly, m *model.MetricsDto, value float64) (float64, bool) {
+	stats, ok := e.stats[key]
+	if !ok {
+		stats = &seriesStats{rollingStats: anomaly.newStats()}
+		e.stats[key] = stats
+	}
+
+	if m.UpdatedAt != nil {
+		if !m.UpdatedAt.After(stats.updatedAt) {
+			return stats.score, stats.anomalous
+		}
+		stats.updatedAt = *m.UpdatedAt
+	}
+
+	score, ok := anomaly.score(stats.rollingStats, value)
+	if !ok || math.Abs(score) <= anomaly.Deviations {
+		stats.score, stats.anomalous = 0, false
+		return 0, false
+	}
+	stats.score, stats.anomalous = score, true
+	return score, true
+}
+
+func (e *Engine) silence(silences []model.Silence, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, a := range e.alerts {
+		a.SilencedBy = nil
+		for i := range silences {
+			if silences[i].Active(now) && silences[i].Matches(a.MetricID, a.Labels) {
+				a.SilencedBy = append(a.SilencedBy, silences[i].ID)
+			}
+		}
+	}
+}
+
+func (e *Engine) activate(rule *Rule, m *model.MetricsDto, value float64, now time.Time) *Alert {
+	probe := &Alert{Rule: rule.Name, MetricID: m.ID, Labels: m.Labels}
+	a, ok := e.alerts[probe.Key()]
+	if !ok || a.State == StateResolved {
+		a = &Alert{
+			Rule:     rule.Name,
+			Group:    rule.Group(m.Labels),
+			MetricID: m.ID,
+			Type:     m.Type,
+			Labels:   m.Labels.Clone(),
+			State:    StatePending,
+			ActiveAt: now,
+		}
+		e.alerts[a.Key()] = a
+	}
+
+	a.Value = value
+	if a.State == StatePending && now.Sub(a.ActiveAt) >= time.Duration(rule.For) {
+		a.State = StateFiring
+		a.FiredAt = &now
+	}
+	return a
+}
+
+func (e *Engine) deactivate(key string, a *Alert, now time.Time) {
+	switch a.State {
+	case StatePending:
+		delete(e.alerts, key)
+	case StateFiring:
+		a.State = StateResolved
+		a.ResolvedAt = &now
+	case StateResolved:
+		if now.Sub(*a.ResolvedAt) > resolvedRetention {
+			delete(e.alerts, key)
+		}
+	}
+}
+
+// Alerts returns copies of current alerts ordered by rule and series.
+func (e *Engine) Alerts() []Alert {
+	e.mu.RLock()
+	alerts := make([]Alert, 0, len(e.alerts))
+	for _, a := range e.alerts {
+		alerts = append(alerts, *a)
+	}
+	e.mu.RUnlock()
+
+	slices.SortFunc(alerts, func(a, b Alert) int {
+		return cmp.Compare(a.Key(), b.Key())
+	})
+	return alerts
+}
+
+func metricValue(m *model.MetricsDto) (float64, bool) {
+	switch {
+	case m.Type == model.Gauge && m.Value != nil:
+		return *m.Value, true
+	case m.Type == model.Counter && m.Delta != nil:
+		return float64(*m.Delta), true
+	default:
+		return 0, false
+	}
+}
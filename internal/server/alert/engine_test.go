@@ -0,0 +1,431 @@
+package alert
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/server/repository"
+	"github.com/yogenyslav/ya-metrics/internal/server/service"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestEngine_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	rule := Rule{
+		Name:      "HighLoad",
+		MetricID:  "load",
+		Type:      model.Gauge,
+		Labels:    model.Labels{"dc": "east"},
+		Op:        OpGreater,
+		Threshold: 1,
+		For:       Duration(time.Minute),
+	}
+	gauge := func(value float64, instance string) *model.MetricsDto {
+		return &model.MetricsDto{
+			ID:     "load",
+			Type:   model.Gauge,
+			Value:  pkg.Ptr(value),
+			Labels: model.Labels{"dc": "east", model.InstanceLabel: instance},
+		}
+	}
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name    string
+		offset  time.Duration
+		metrics []*model.MetricsDto
+		want    map[string]State
+	}{
+		{
+			name:    "Condition starts holding",
+			metrics: []*model.MetricsDto{gauge(2, "a"), gauge(0.5, "b")},
+			want:    map[string]State{"a": StatePending},
+		},
+		{
+			name:    "Condition holds shorter than for",
+			offset:  30 * time.Second,
+			metrics: []*model.MetricsDto{gauge(3, "a"), gauge(2, "b")},
+			want:    map[string]State{"a": StatePending, "b": StatePending},
+		},
+		{
+			name:    "Condition holds for the rule duration",
+			offset:  time.Minute,
+			metrics: []*model.MetricsDto{gauge(3, "a"), gauge(0.1, "b")},
+			want:    map[string]State{"a": StateFiring},
+		},
+		{
+			name:    "Condition stops holding",
+			offset:  2 * time.Minute,
+			metrics: []*model.MetricsDto{gauge(0.2, "a")},
+			want:    map[string]State{"a": StateResolved},
+		},
+		{
+			name:    "Resolved alert becomes pending again",
+			offset:  3 * time.Minute,
+			metrics: []*model.MetricsDto{gauge(5, "a")},
+			want:    map[string]State{"a": StatePending},
+		},
+	}
+
+	ms := &mocks.MockMetricService{}
+	engine := New([]Rule{rule}, ms, repository.NewSilenceInMemRepo(nil), nil)
+
+	for _, step := range steps {
+		ms.On("ListMetrics", mock.Anything).Return(step.metrics, nil)
+
+		require.NoError(t, engine.Evaluate(context.Background(), base.Add(step.offset)), step.name)
+
+		got := make(map[string]State)
+		for _, a := range engine.Alerts() {
+			got[a.Labels[model.InstanceLabel]] = a.State
+		}
+		assert.Equal(t, step.want, got, step.name)
+	}
+
+	alerts := engine.Alerts()
+	require.Len(t, alerts, 1)
+	assert.Equal(t, base.Add(3*time.Minute), alerts[0].ActiveAt)
+	assert.InDelta(t, 5.0, alerts[0].Value, 1e-9)
+}
+
+func TestEngine_Evaluate_resolvedExpire(t *testing.T) {
+	t.Parallel()
+
+	rule := Rule{Name: "Polls", MetricID: "PollCount", Type: model.Counter, Op: OpGreater, Threshold: 10}
+	counter := &model.MetricsDto{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr(int64(20))}
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	ms := &mocks.MockMetricService{}
+	engine := New([]Rule{rule}, ms, repository.NewSilenceInMemRepo(nil), nil)
+
+	ms.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{counter}, nil)
+	require.NoError(t, engine.Evaluate(context.Background(), base))
+	require.Len(t, engine.Alerts(), 1)
+	assert.Equal(t, StateFiring, engine.Alerts()[0].State)
+
+	ms.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{}, nil)
+	require.NoError(t, engine.Evaluate(context.Background(), base.Add(time.Minute)))
+	assert.Equal(t, StateResolved, engine.Alerts()[0].State)
+
+	ms.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{}, nil)
+	require.NoError(t, engine.Evaluate(context.Background(), base.Add(time.Minute+resolvedRetention+time.Second)))
+	assert.Empty(t, engine.Alerts())
+}
+
+func TestEngine_Evaluate_silenced(t *testing.T) {
+	t.Parallel()
+
+	rule := Rule{Name: "HighLoad", MetricID: "load", Type: model.Gauge, Op: OpGreater, Threshold: 1}
+	gauge := func(instance string) *model.MetricsDto {
+		return &model.MetricsDto{
+			ID:     "load",
+			Type:   model.Gauge,
+			Value:  pkg.Ptr(2.0),
+			Labels: model.Labels{model.InstanceLabel: instance},
+		}
+	}
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	ms := &mocks.MockMetricService{}
+	metrics := []*model.MetricsDto{gauge("a"), gauge("b")}
+	engine := New([]Rule{rule}, ms, repository.NewSilenceInMemRepo(nil), nil)
+
+	silence, err := engine.CreateSilence(context.Background(), model.Silence{
+		MetricID: "load",
+		Labels:   model.Labels{model.InstanceLabel: "a"},
+		StartsAt: base,
+		EndsAt:   base.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, silence.ID)
+
+	silencedBy := func() map[string][]string {
+		got := make(map[string][]string)
+		for _, a := range engine.Alerts() {
+			got[a.Labels[model.InstanceLabel]] = a.SilencedBy
+		}
+		return got
+	}
+
+	ms.On("ListMetrics", mock.Anything).Return(metrics, nil)
+	require.NoError(t, engine.Evaluate(context.Background(), base.Add(time.Minute)))
+	assert.Equal(t, map[string][]string{"a": {silence.ID}, "b": nil}, silencedBy())
+
+	ms.On("ListMetrics", mock.Anything).Return(metrics, nil)
+	require.NoError(t, engine.Evaluate(context.Background(), base.Add(time.Hour)))
+	assert.Equal(t, map[string][]string{"a": nil, "b": nil}, silencedBy())
+}
+
+func TestEngine_CreateSilence(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		silence model.Silence
+		wantErr error
+	}{
+		{
+			name:    "Starts now by default",
+			silence: model.Silence{MetricID: "load", EndsAt: now.Add(time.Hour)},
+		},
+		{
+			name:    "Matches every alert",
+			silence: model.Silence{StartsAt: now, EndsAt: now.Add(time.Hour)},
+			wantErr: errs.ErrInvalidSilence,
+		},
+		{
+			name:    "Ends before start",
+			silence: model.Silence{MetricID: "load", StartsAt: now, EndsAt: now.Add(-time.Hour)},
+			wantErr: errs.ErrInvalidSilence,
+		},
+		{
+			name: "Invalid labels",
+			silence: model.Silence{
+				Labels:   model.Labels{"1dc": "east"},
+				StartsAt: now,
+				EndsAt:   now.Add(time.Hour),
+			},
+			wantErr: errs.ErrInvalidSilence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			engine := New(nil, nil, repository.NewSilenceInMemRepo(nil), nil)
+
+			got, err := engine.CreateSilence(context.Background(), tt.silence)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, got.Active(time.Now()))
+
+			silences, err := engine.Silences(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, []model.Silence{got}, silences)
+
+			require.NoError(t, engine.ExpireSilence(context.Background(), got.ID))
+			silences, err = engine.Silences(context.Background())
+			require.NoError(t, err)
+			assert.False(t, silences[0].Active(time.Now()))
+
+			require.ErrorIs(t, engine.ExpireSilence(context.Background(), "unknown"), errs.ErrSilenceNotFound)
+		})
+	}
+}
+
+func TestEngine_Evaluate_anomaly(t *testing.T) {
+	t.Parallel()
+
+	rule := Rule{
+		Name:     "HeapSpike",
+		MetricID: "HeapAlloc",
+		Type:     model.Gauge,
+		Anomaly:  &Anomaly{Method: MethodZScore, Deviations: 3, Window: 4},
+	}
+	gauge := func(value float64, instance string) *model.MetricsDto {
+		return &model.MetricsDto{
+			ID:     "HeapAlloc",
+			Type:   model.Gauge,
+			Value:  pkg.Ptr(value),
+			Labels: model.Labels{model.InstanceLabel: instance},
+		}
+	}
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		metrics []*model.MetricsDto
+		want    map[string]State
+	}{
+		{metrics: []*model.MetricsDto{gauge(2, "a"), gauge(200, "b")}, want: map[string]State{}},
+		{metrics: []*model.MetricsDto{gauge(4, "a"), gauge(400, "b")}, want: map[string]State{}},
+		{metrics: []*model.MetricsDto{gauge(2, "a"), gauge(200, "b")}, want: map[string]State{}},
+		{metrics: []*model.MetricsDto{gauge(4, "a"), gauge(400, "b")}, want: map[string]State{}},
+		// 9 is far from the history of a, but within the one of b
+		{metrics: []*model.MetricsDto{gauge(9, "a"), gauge(350, "b")}, want: map[string]State{"a": StateFiring}},
+		{metrics: []*model.MetricsDto{gauge(4, "a"), gauge(300, "b")}, want: map[string]State{"a": StateResolved}},
+	}
+
+	ms := &mocks.MockMetricService{}
+	engine := New([]Rule{rule}, ms, repository.NewSilenceInMemRepo(nil), nil)
+
+	for i, step := range steps {
+		ms.On("ListMetrics", mock.Anything).Return(step.metrics, nil)
+		require.NoError(t, engine.Evaluate(context.Background(), base.Add(time.Duration(i)*time.Minute)))
+
+		got := make(map[string]State)
+		for _, a := range engine.Alerts() {
+			got[a.Labels[model.InstanceLabel]] = a.State
+		}
+		assert.Equal(t, step.want, got, "step %d", i)
+	}
+
+	alerts := engine.Alerts()
+	require.Len(t, alerts, 1)
+	assert.InDelta(t, 6.0, alerts[0].Score, 1e-9)
+
+	// history of a series gone from the metrics is dropped
+	ms.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{gauge(4, "a")}, nil)
+	require.NoError(t, engine.Evaluate(context.Background(), base.Add(time.Hour)))
+	assert.Len(t, engine.stats, 1)
+}
+
+func TestEngine_Evaluate_anomalyWithoutUpdate(t *testing.T) {
+	t.Parallel()
+
+	rule := Rule{
+		Name:     "HeapSpike",
+		MetricID: "HeapAlloc",
+		Type:     model.Gauge,
+		Anomaly:  &Anomaly{Method: MethodZScore, Deviations: 3, Window: 4},
+	}
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	gauge := func(value float64, updatedAt time.Time) *model.MetricsDto {
+		return &model.MetricsDto{ID: "HeapAlloc", Type: model.Gauge, Value: pkg.Ptr(value), UpdatedAt: &updatedAt}
+	}
+
+	steps := []struct {
+		metric *model.MetricsDto
+		want   []State
+	}{
+		{metric: gauge(2, base)},
+		{metric: gauge(4, base.Add(time.Minute))},
+		{metric: gauge(2, base.Add(2*time.Minute))},
+		{metric: gauge(4, base.Add(3*time.Minute))},
+		// the same value is evaluated again and must not fill the window
+		{metric: gauge(4, base.Add(3*time.Minute))},
+		{metric: gauge(4, base.Add(3*time.Minute))},
+		{metric: gauge(4, base.Add(3*time.Minute))},
+		{metric: gauge(9, base.Add(4*time.Minute)), want: []State{StateFiring}},
+		// the spike is still the last value, the alert keeps firing
+		{metric: gauge(9, base.Add(4*time.Minute)), want: []State{StateFiring}},
+	}
+
+	ms := &mocks.MockMetricService{}
+	engine := New([]Rule{rule}, ms, repository.NewSilenceInMemRepo(nil), nil)
+
+	for i, step := range steps {
+		ms.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{step.metric}, nil).Once()
+		require.NoError(t, engine.Evaluate(context.Background(), base.Add(time.Duration(i)*time.Minute)))
+
+		var got []State
+		for _, a := range engine.Alerts() {
+			got = append(got, a.State)
+		}
+		assert.Equal(t, step.want, got, "step %d", i)
+	}
+
+	alerts := engine.Alerts()
+	require.Len(t, alerts, 1)
+	assert.InDelta(t, 6.0, alerts[0].Score, 1e-9)
+}
+
+func TestEngine_Evaluate_absent(t *testing.T) {
+	t.Parallel()
+
+	rule := Rule{
+		Name:     "AgentDown",
+		MetricID: "PollCount",
+		Type:     model.Counter,
+		Labels:   model.Labels{"dc": "east"},
+		Absent:   Duration(5 * time.Minute),
+	}
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	counter := func(instance string, updatedAt time.Time) *model.MetricsDto {
+		return &model.MetricsDto{
+			ID:        "PollCount",
+			Type:      model.Counter,
+			Delta:     pkg.Ptr(int64(1)),
+			Labels:    model.Labels{"dc": "east", model.InstanceLabel: instance},
+			UpdatedAt: &updatedAt,
+		}
+	}
+
+	steps := []struct {
+		name    string
+		metrics []*model.MetricsDto
+		want    map[string]float64
+	}{
+		{
+			name:    "Series updated recently",
+			metrics: []*model.MetricsDto{counter("a", base), counter("b", base.Add(-time.Minute))},
+			want:    map[string]float64{},
+		},
+		{
+			name:    "Series not updated for the rule duration",
+			metrics: []*model.MetricsDto{counter("a", base), counter("b", base.Add(-10*time.Minute))},
+			want:    map[string]float64{"PollCount{dc=\"east\",instance=\"b\"}": 600},
+		},
+		{
+			name:    "Metric has no series",
+			metrics: []*model.MetricsDto{},
+			want:    map[string]float64{"PollCount{dc=\"east\"}": 0},
+		},
+	}
+
+	for _, step := range steps {
+		ms := &mocks.MockMetricService{}
+		ms.On("ListMetrics", mock.Anything).Return(step.metrics, nil)
+		engine := New([]Rule{rule}, ms, repository.NewSilenceInMemRepo(nil), nil)
+
+		require.NoError(t, engine.Evaluate(context.Background(), base), step.name)
+
+		got := make(map[string]float64)
+		for _, a := range engine.Alerts() {
+			assert.Equal(t, StateFiring, a.State, step.name)
+			got[model.SeriesKey(a.MetricID, a.Labels)] = a.Value
+		}
+		assert.Equal(t, step.want, got, step.name)
+	}
+}
+
+func TestEngine_Evaluate_absentHistogramAndSummary(t *testing.T) {
+	t.Parallel()
+
+	svc := service.NewService(
+		repository.NewMetricInMemRepo(repository.StorageState[float64]{}),
+		repository.NewMetricInMemRepo(repository.StorageState[int64]{}),
+		repository.NewHistogramInMemRepo(nil),
+		repository.NewSummaryInMemRepo(nil),
+		nil,
+	)
+	ctx := context.Background()
+	require.NoError(t, svc.UpdateMetric(ctx, &model.MetricsDto{
+		ID:        "latency",
+		Type:      model.Histogram,
+		Histogram: &model.HistogramData{Bounds: []float64{1}, Counts: []int64{1, 0}, Count: 1, Sum: 0.5},
+	}))
+	require.NoError(t, svc.UpdateMetric(ctx, &model.MetricsDto{
+		ID:    "duration",
+		Type:  model.Summary,
+		Value: pkg.Ptr(0.5),
+	}))
+
+	rules := []Rule{
+		{Name: "LatencyAbsent", MetricID: "latency", Type: model.Histogram, Absent: Duration(5 * time.Minute)},
+		{Name: "DurationAbsent", MetricID: "duration", Type: model.Summary, Absent: Duration(5 * time.Minute)},
+	}
+	engine := New(rules, svc, repository.NewSilenceInMemRepo(nil), nil)
+
+	require.NoError(t, engine.Evaluate(ctx, time.Now()))
+	assert.Empty(t, engine.Alerts())
+
+	require.NoError(t, engine.Evaluate(ctx, time.Now().Add(10*time.Minute)))
+	alerts := engine.Alerts()
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "duration", alerts[0].MetricID)
+	assert.Equal(t, "latency", alerts[1].MetricID)
+}
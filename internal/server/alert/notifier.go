@@ -0,0 +1,213 @@
+package alert
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/config"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/pkg/retry"
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
+)
+
+const (
+	defaultWebhookTimeoutSec = 3
+	signatureHeader          = "HashSHA256"
+)
+
+// Notification is the payload delivered to webhooks for a group of alerts.
+//
+// Status is firing while any alert of the group fires, resolved alerts are delivered once.
+type Notification struct {
+	Group  string  `json:"group"`
+	Status State   `json:"status"`
+	Alerts []Alert `json:"alerts"`
+}
+
+type sender interface {
+	Send(ctx context.Context, data []byte) error
+}
+
+type webhook struct {
+	url    string
+	client *http.Client
+	sg     *secure.SignatureGenerator
+}
+
+// Send posts the payload, responses with 4xx status are not retried.
+func (wh *webhook) Send(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.url, bytes.NewReader(data))
+	if err != nil {
+		return errs.Wrap(errors.Join(retry.ErrUnretriable, err), "create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if wh.sg != nil {
+		req.Header.Set(signatureHeader, wh.sg.SignatureSHA256(data))
+	}
+
+	resp, err := wh.client.Do(req)
+	if err != nil {
+		return errs.Wrap(err, "send webhook request")
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("webhook %s responded with status %d", wh.url, resp.StatusCode)
+	case resp.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("%w: webhook %s responded with status %d", retry.ErrUnretriable, wh.url, resp.StatusCode)
+	default:
+		return nil
+	}
+}
+
+type groupState struct {
+	firing map[string]struct{}
+	sentAt time.Time
+}
+
+// Notifier delivers alert state changes to webhooks.
+//
+// Alerts are notified by groups, a group is sent again only when its set of firing alerts changes
+// or after the repeat interval while it keeps firing.
+type Notifier struct {
+	senders        []sender
+	retry          *retry.Config
+	repeatInterval time.Duration
+	groups         map[string]*groupState
+	mu             *sync.Mutex
+}
+
+// NewNotifier creates a new Notifier instance, payloads are signed when key is not empty.
+func NewNotifier(cfg *config.AlertConfig, key string, retryCfg *retry.Config) *Notifier {
+	var sg *secure.SignatureGenerator
+	if key != "" {
+		sg = secure.NewSignatureGenerator(key)
+	}
+
+	senders := make([]sender, 0, len(cfg.WebhookURLs))
+	for _, url := range cfg.WebhookURLs {
+		senders = append(senders, &webhook{
+			url: url,
+			client: &http.Client{
+				Timeout: time.Second * defaultWebhookTimeoutSec,
+			},
+			sg: sg,
+		})
+	}
+
+	return &Notifier{
+		senders:        senders,
+		retry:          retryCfg,
+		repeatInterval: time.Second * time.Duration(cfg.RepeatInterval),
+		groups:         make(map[string]*groupState),
+		mu:             &sync.Mutex{},
+	}
+}
+
+// Notify sends notifications for groups of alerts which changed or are due to be repeated.
+//
+// Silenced alerts are left out as if they were not active.
+// A group failed to be delivered is sent again on the next call.
+func (n *Notifier) Notify(ctx context.Context, now time.Time, alerts []Alert) error {
+	if len(n.senders) == 0 {
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	groups := make(map[string][]Alert)
+	for _, a := range alerts {
+		if a.State != StatePending && !a.Silenced() {
+			groups[a.Group] = append(groups[a.Group], a)
+		}
+	}
+
+	for group := range n.groups {
+		if _, ok := groups[group]; !ok {
+			delete(n.groups, group)
+		}
+	}
+
+	var errList []error
+	for _, group := range slices.Sorted(maps.Keys(groups)) {
+		notification, firing, ok := n.prepare(group, groups[group], now)
+		if !ok {
+			continue
+		}
+
+		if err := n.send(ctx, notification); err != nil {
+			errList = append(errList, errs.Wrap(err, "notify group "+group))
+			continue
+		}
+		n.groups[group] = &groupState{firing: firing, sentAt: now}
+	}
+
+	return errors.Join(errList...)
+}
+
+// prepare builds the group notification if it has to be sent.
+func (n *Notifier) prepare(group string, alerts []Alert, now time.Time) (*Notification, map[string]struct{}, bool) {
+	state, ok := n.groups[group]
+	if !ok {
+		state = &groupState{}
+	}
+
+	notification := &Notification{Group: group, Status: StateResolved}
+	firing := make(map[string]struct{})
+	for _, a := range alerts {
+		key := a.Key()
+		switch a.State {
+		case StateFiring:
+			firing[key] = struct{}{}
+			notification.Status = StateFiring
+		case StateResolved:
+			// resolved alerts are only delivered to those who were notified of them firing
+			if _, wasFiring := state.firing[key]; !wasFiring {
+				continue
+			}
+		default:
+			continue
+		}
+		notification.Alerts = append(notification.Alerts, a)
+	}
+
+	changed := !maps.Equal(firing, state.firing) || len(notification.Alerts) > len(firing)
+	due := len(firing) > 0 && now.Sub(state.sentAt) >= n.repeatInterval
+	if len(notification.Alerts) == 0 || (!changed && !due) {
+		return nil, nil, false
+	}
+
+	slices.SortFunc(notification.Alerts, func(a, b Alert) int {
+		return cmp.Compare(a.Key(), b.Key())
+	})
+	return notification, firing, true
+}
+
+func (n *Notifier) send(ctx context.Context, notification *Notification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return errs.Wrap(err, "marshal notification")
+	}
+
+	var errList []error
+	for _, s := range n.senders {
+		err := retry.WithLinearBackoffRetry(ctx, n.retry, func(ctx context.Context) error {
+			return s.Send(ctx, data)
+		})
+		if err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return errors.Join(errList...)
+}
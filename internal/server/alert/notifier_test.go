@@ -0,0 +1,225 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/retry"
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
+)
+
+type webhookRecorder struct {
+	statuses      []int
+	notifications []Notification
+	signatures    []string
+	mu            sync.Mutex
+}
+
+func (wr *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	status := http.StatusOK
+	if len(wr.statuses) > 0 {
+		status, wr.statuses = wr.statuses[0], wr.statuses[1:]
+	}
+	if status == http.StatusOK {
+		body, _ := io.ReadAll(r.Body)
+		var n Notification
+		_ = json.Unmarshal(body, &n)
+		wr.notifications = append(wr.notifications, n)
+		wr.signatures = append(wr.signatures, r.Header.Get(signatureHeader))
+	}
+	w.WriteHeader(status)
+}
+
+func testAlert(instance string, state State) Alert {
+	return Alert{
+		Rule:     "HighLoad",
+		Group:    "HighLoad",
+		MetricID: "load",
+		Type:     model.Gauge,
+		Labels:   model.Labels{model.InstanceLabel: instance},
+		State:    state,
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := NewNotifier(&config.AlertConfig{
+		WebhookURLs:    []string{srv.URL},
+		RepeatInterval: 3600,
+	}, "secret", nil)
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		name   string
+		offset time.Duration
+		alerts []Alert
+		want   *Notification
+	}{
+		{
+			name:   "Pending alerts are not notified",
+			alerts: []Alert{testAlert("a", StatePending)},
+		},
+		{
+			name:   "Firing alert is notified",
+			offset: time.Minute,
+			alerts: []Alert{testAlert("a", StateFiring), testAlert("b", StatePending)},
+			want: &Notification{
+				Group:  "HighLoad",
+				Status: StateFiring,
+				Alerts: []Alert{testAlert("a", StateFiring)},
+			},
+		},
+		{
+			name:   "Repeated firing is deduplicated",
+			offset: 2 * time.Minute,
+			alerts: []Alert{testAlert("a", StateFiring), testAlert("b", StatePending)},
+		},
+		{
+			name:   "New firing alert in the group is notified",
+			offset: 3 * time.Minute,
+			alerts: []Alert{testAlert("a", StateFiring), testAlert("b", StateFiring)},
+			want: &Notification{
+				Group:  "HighLoad",
+				Status: StateFiring,
+				Alerts: []Alert{testAlert("a", StateFiring), testAlert("b", StateFiring)},
+			},
+		},
+		{
+			name:   "Resolved alert is notified once",
+			offset: 4 * time.Minute,
+			alerts: []Alert{testAlert("a", StateResolved), testAlert("b", StateFiring)},
+			want: &Notification{
+				Group:  "HighLoad",
+				Status: StateFiring,
+				Alerts: []Alert{testAlert("a", StateResolved), testAlert("b", StateFiring)},
+			},
+		},
+		{
+			name:   "Still firing group is not notified before repeat interval",
+			offset: 5 * time.Minute,
+			alerts: []Alert{testAlert("a", StateResolved), testAlert("b", StateFiring)},
+		},
+		{
+			name:   "Still firing group is notified after repeat interval",
+			offset: 4*time.Minute + time.Hour,
+			alerts: []Alert{testAlert("a", StateResolved), testAlert("b", StateFiring)},
+			want: &Notification{
+				Group:  "HighLoad",
+				Status: StateFiring,
+				Alerts: []Alert{testAlert("b", StateFiring)},
+			},
+		},
+		{
+			name:   "Group resolves",
+			offset: 5*time.Minute + time.Hour,
+			alerts: []Alert{testAlert("a", StateResolved), testAlert("b", StateResolved)},
+			want: &Notification{
+				Group:  "HighLoad",
+				Status: StateResolved,
+				Alerts: []Alert{testAlert("b", StateResolved)},
+			},
+		},
+	}
+
+	for _, step := range steps {
+		sent := len(rec.notifications)
+		require.NoError(t, n.Notify(context.Background(), base.Add(step.offset), step.alerts), step.name)
+
+		if step.want == nil {
+			assert.Len(t, rec.notifications, sent, step.name)
+			continue
+		}
+		require.Len(t, rec.notifications, sent+1, step.name)
+		assert.Equal(t, *step.want, rec.notifications[sent], step.name)
+	}
+
+	data, err := json.Marshal(rec.notifications[0])
+	require.NoError(t, err)
+	assert.Equal(t, secure.NewSignatureGenerator("secret").SignatureSHA256(data), rec.signatures[0])
+}
+
+func TestNotifier_Notify_retry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantErr   bool
+		wantSent  int
+		wantRetry bool
+	}{
+		{name: "Server error is retried", statuses: []int{http.StatusBadGateway}, wantSent: 1},
+		{name: "Client error is not retried", statuses: []int{http.StatusBadRequest}, wantErr: true, wantRetry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := &webhookRecorder{statuses: tt.statuses}
+			srv := httptest.NewServer(rec)
+			defer srv.Close()
+
+			n := NewNotifier(
+				&config.AlertConfig{WebhookURLs: []string{srv.URL}, RepeatInterval: 3600},
+				"",
+				&retry.Config{MaxRetries: 2},
+			)
+			now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+			err := n.Notify(context.Background(), now, []Alert{testAlert("a", StateFiring)})
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, rec.notifications, tt.wantSent)
+			assert.Empty(t, rec.statuses)
+
+			if tt.wantRetry {
+				// undelivered group is sent on the next call
+				require.NoError(t, n.Notify(context.Background(), now, []Alert{testAlert("a", StateFiring)}))
+				assert.Len(t, rec.notifications, 1)
+			}
+		})
+	}
+}
+
+func TestNotifier_Notify_silenced(t *testing.T) {
+	t.Parallel()
+
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := NewNotifier(&config.AlertConfig{WebhookURLs: []string{srv.URL}, RepeatInterval: 3600}, "", nil)
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	silenced := testAlert("a", StateFiring)
+	silenced.SilencedBy = []string{"maintenance"}
+	require.NoError(t, n.Notify(context.Background(), now, []Alert{silenced}))
+	assert.Empty(t, rec.notifications)
+
+	// the alert is notified once the silence ends while it still fires
+	require.NoError(t, n.Notify(context.Background(), now.Add(time.Minute), []Alert{testAlert("a", StateFiring)}))
+	require.Len(t, rec.notifications, 1)
+	assert.Equal(t, []Alert{testAlert("a", StateFiring)}, rec.notifications[0].Alerts)
+}
@@ -0,0 +1,185 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// Comparison operators supported by rules.
+const (
+	OpGreater      = ">"
+	OpGreaterEqual = ">="
+	OpLess         = "<"
+	OpLessEqual    = "<="
+	OpEqual        = "=="
+	OpNotEqual     = "!="
+)
+
+// Duration is a time.Duration represented in JSON as a string like "5m".
+type Duration time.Duration
+
+// UnmarshalJSON parses the duration from a string.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON formats the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Rule describes a condition on metric series which fires an alert when it holds for the For duration.
+//
+// A rule selects every series of the metric having all of its Labels.
+// Alerts of the rule are notified together unless GroupBy splits them by values of the listed labels.
+// A rule with Anomaly compares every value with the history of its series instead of the static Threshold.
+// A rule with Absent fires for series not updated for that long and once for the metric if it has no series at all,
+// it is the only kind of rule accepting histograms and summaries.
+type Rule struct {
+	Name      string       `json:"name"`
+	MetricID  string       `json:"metric"`
+	Type      string       `json:"type"`
+	Labels    model.Labels `json:"labels,omitempty"`
+	Op        string       `json:"op"`
+	Threshold float64      `json:"threshold"`
+	For       Duration     `json:"for"`
+	GroupBy   []string     `json:"group_by,omitempty"`
+	Anomaly   *Anomaly     `json:"anomaly,omitempty"`
+	Absent    Duration     `json:"absent,omitempty"`
+}
+
+// Validate checks that the rule can be evaluated.
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("rule name is empty")
+	}
+	if r.MetricID == "" {
+		return fmt.Errorf("rule %s: metric is empty", r.Name)
+	}
+	switch r.Type {
+	case model.Gauge, model.Counter:
+	case model.Histogram, model.Summary:
+		if r.Absent <= 0 {
+			return fmt.Errorf("rule %s: metric type %q is supported only by absent rules", r.Name, r.Type)
+		}
+	default:
+		return fmt.Errorf("rule %s: unsupported metric type %q", r.Name, r.Type)
+	}
+	if !r.Labels.Valid() {
+		return fmt.Errorf("rule %s: invalid labels", r.Name)
+	}
+	if r.For < 0 {
+		return fmt.Errorf("rule %s: negative for duration", r.Name)
+	}
+	if r.Absent < 0 {
+		return fmt.Errorf("rule %s: negative absent duration", r.Name)
+	}
+	if r.Absent > 0 {
+		if r.Anomaly != nil {
+			return fmt.Errorf("rule %s: absent rule cannot detect anomalies", r.Name)
+		}
+		return nil
+	}
+	if r.Anomaly != nil {
+		if err := r.Anomaly.Validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		return nil
+	}
+
+	switch r.Op {
+	case OpGreater, OpGreaterEqual, OpLess, OpLessEqual, OpEqual, OpNotEqual:
+		return nil
+	default:
+		return fmt.Errorf("rule %s: unsupported comparison %q", r.Name, r.Op)
+	}
+}
+
+// Selects reports whether the metric series is evaluated by the rule.
+func (r *Rule) Selects(m *model.MetricsDto) bool {
+	if m.ID != r.MetricID || m.Type != r.Type {
+		return false
+	}
+
+	for name, value := range r.Labels {
+		if m.Labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Group returns the notification group of the series alert.
+func (r *Rule) Group(labels model.Labels) string {
+	group := make(model.Labels, len(r.GroupBy))
+	for _, name := range r.GroupBy {
+		group[name] = labels[name]
+	}
+	return model.SeriesKey(r.Name, group)
+}
+
+// Holds reports whether the value satisfies the static rule condition.
+func (r *Rule) Holds(value float64) bool {
+	switch r.Op {
+	case OpGreater:
+		return value > r.Threshold
+	case OpGreaterEqual:
+		return value >= r.Threshold
+	case OpLess:
+		return value < r.Threshold
+	case OpLessEqual:
+		return value <= r.Threshold
+	case OpEqual:
+		return value == r.Threshold
+	case OpNotEqual:
+		return value != r.Threshold
+	default:
+		return false
+	}
+}
+
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules reads rules from the JSON file.
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errs.Wrap(err, "read rules file")
+	}
+
+	var f rulesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, errs.Wrap(err, "unmarshal rules")
+	}
+
+	names := make(map[string]struct{}, len(f.Rules))
+	for i := range f.Rules {
+		if err := f.Rules[i].Validate(); err != nil {
+			return nil, errs.Wrap(err, "validate rules")
+		}
+		if _, ok := names[f.Rules[i].Name]; ok {
+			return nil, errs.Wrap(fmt.Errorf("duplicate rule %s", f.Rules[i].Name), "validate rules")
+		}
+		names[f.Rules[i].Name] = struct{}{}
+	}
+
+	return f.Rules, nil
+}
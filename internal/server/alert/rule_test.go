@@ -0,0 +1,171 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []Rule
+		wantErr bool
+	}{
+		{
+			name: "Valid rules",
+			data: `{"rules": [
+				{"name": "HighCPU", "metric": "CPUutilization1", "type": "gauge",
+					"op": ">", "threshold": 90, "for": "5m"},
+				{"name": "NoPolls", "metric": "PollCount", "type": "counter", "labels": {"instance": "a"},
+					"op": "==", "threshold": 0, "for": "0s"}
+			]}`,
+			want: []Rule{
+				{
+					Name:      "HighCPU",
+					MetricID:  "CPUutilization1",
+					Type:      model.Gauge,
+					Op:        OpGreater,
+					Threshold: 90,
+					For:       Duration(5 * time.Minute),
+				},
+				{
+					Name:     "NoPolls",
+					MetricID: "PollCount",
+					Type:     model.Counter,
+					Labels:   model.Labels{"instance": "a"},
+					Op:       OpEqual,
+				},
+			},
+		},
+		{
+			name: "Anomaly rule",
+			data: `{"rules": [
+				{"name": "HeapSpike", "metric": "HeapAlloc", "type": "gauge", "for": "1m",
+					"anomaly": {"method": "ewma", "alpha": 0.1, "deviations": 3}}
+			]}`,
+			want: []Rule{
+				{
+					Name:     "HeapSpike",
+					MetricID: "HeapAlloc",
+					Type:     model.Gauge,
+					For:      Duration(time.Minute),
+					Anomaly:  &Anomaly{Method: MethodEWMA, Alpha: 0.1, Deviations: 3},
+				},
+			},
+		},
+		{
+			name: "Invalid anomaly",
+			data: `{"rules": [{"name": "r", "metric": "m", "type": "gauge", "for": "1m",
+				"anomaly": {"method": "zscore", "deviations": 3}}]}`,
+			wantErr: true,
+		},
+		{
+			name: "Absent rule",
+			data: `{"rules": [{"name": "AgentDown", "metric": "PollCount", "type": "counter", "for": "0s",
+				"absent": "5m"}]}`,
+			want: []Rule{
+				{Name: "AgentDown", MetricID: "PollCount", Type: model.Counter, Absent: Duration(5 * time.Minute)},
+			},
+		},
+		{
+			name: "Absent histogram and summary rules",
+			data: `{"rules": [
+				{"name": "NoLatency", "metric": "RequestLatency", "type": "histogram", "for": "0s", "absent": "5m"},
+				{"name": "NoDuration", "metric": "GCPause", "type": "summary", "for": "0s", "absent": "1m"}
+			]}`,
+			want: []Rule{
+				{
+					Name:     "NoLatency",
+					MetricID: "RequestLatency",
+					Type:     model.Histogram,
+					Absent:   Duration(5 * time.Minute),
+				},
+				{Name: "NoDuration", MetricID: "GCPause", Type: model.Summary, Absent: Duration(time.Minute)},
+			},
+		},
+		{
+			name: "Absent anomaly rule",
+			data: `{"rules": [{"name": "r", "metric": "m", "type": "gauge", "for": "1m", "absent": "5m",
+				"anomaly": {"method": "ewma", "alpha": 0.1, "deviations": 3}}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Unknown comparison",
+			data:    `{"rules": [{"name": "r", "metric": "m", "type": "gauge", "op": "~", "for": "1m"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported metric type",
+			data:    `{"rules": [{"name": "r", "metric": "m", "type": "histogram", "op": ">", "for": "1m"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Unknown metric type",
+			data:    `{"rules": [{"name": "r", "metric": "m", "type": "set", "for": "1m", "absent": "5m"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid duration",
+			data:    `{"rules": [{"name": "r", "metric": "m", "type": "gauge", "op": ">", "for": "soon"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "Duplicate names",
+			data: `{"rules": [
+				{"name": "r", "metric": "m", "type": "gauge", "op": ">", "for": "1m"},
+				{"name": "r", "metric": "m", "type": "gauge", "op": "<", "for": "1m"}
+			]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "rules.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.data), 0o600))
+
+			got, err := LoadRules(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRule_Holds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		op    string
+		value float64
+		want  bool
+	}{
+		{op: OpGreater, value: 11, want: true},
+		{op: OpGreater, value: 10, want: false},
+		{op: OpGreaterEqual, value: 10, want: true},
+		{op: OpLess, value: 9, want: true},
+		{op: OpLess, value: 10, want: false},
+		{op: OpLessEqual, value: 10, want: true},
+		{op: OpEqual, value: 10, want: true},
+		{op: OpNotEqual, value: 10, want: false},
+	}
+
+	for _, tt := range tests {
+		rule := Rule{Op: tt.op, Threshold: 10}
+		assert.Equal(t, tt.want, rule.Holds(tt.value), "%v %s 10", tt.value, tt.op)
+	}
+}
@@ -0,0 +1,44 @@
+package alert
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// CreateSilence validates and stores a new silence, it starts right away unless StartsAt is set.
+//
+// The silence takes effect on the next evaluation.
+func (e *Engine) CreateSilence(ctx context.Context, silence model.Silence) (model.Silence, error) {
+	now := time.Now()
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = now
+	}
+	if err := silence.Validate(); err != nil {
+		return model.Silence{}, errs.Wrap(errs.ErrInvalidSilence, err.Error())
+	}
+
+	silence.ID = rand.Text()
+	silence.CreatedAt = now
+	if err := e.silences.Create(ctx, silence); err != nil {
+		return model.Silence{}, errs.Wrap(err, "create silence")
+	}
+	return silence, nil
+}
+
+// Silences returns all silences including the expired ones.
+func (e *Engine) Silences(ctx context.Context) ([]model.Silence, error) {
+	silences, err := e.silences.List(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err, "list silences")
+	}
+	return silences, nil
+}
+
+// ExpireSilence ends the silence now.
+func (e *Engine) ExpireSilence(ctx context.Context, id string) error {
+	return errs.Wrap(e.silences.Expire(ctx, id, time.Now()), "expire silence")
+}
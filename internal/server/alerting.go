@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// RuleEvaluator evaluates alerting rules.
+type RuleEvaluator interface {
+	Evaluate(ctx context.Context, now time.Time) error
+}
+
+// Start evaluating alerting rules.
+func (s *Server) Alerting(ctx context.Context, evaluator RuleEvaluator, newTicker TickerFactory) {
+	if s.cfg.Alert.RulesFile == "" || s.cfg.Alert.EvalInterval <= 0 {
+		return
+	}
+
+	ticker := newTicker(time.Second * time.Duration(s.cfg.Alert.EvalInterval))
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		defer ticker.Stop()
+
+		var err error
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C():
+				err = evaluator.Evaluate(context.Background(), now)
+				if err != nil {
+					log.Err(errs.Wrap(err)).Msg("evaluate alerting rules")
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yogenyslav/ya-metrics/internal/config"
+)
+
+type mockRuleEvaluator struct {
+	mock.Mock
+
+	called chan struct{}
+}
+
+func (e *mockRuleEvaluator) Evaluate(ctx context.Context, now time.Time) error {
+	args := e.Called(ctx, now)
+	e.called <- struct{}{}
+	return args.Error(0)
+}
+
+func TestServer_Alerting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticker := &testTicker{ch: make(chan time.Time)}
+	getTicker := func(time.Duration) Ticker { return ticker }
+	evaluator := &mockRuleEvaluator{called: make(chan struct{}, 1)}
+	now := time.Now()
+
+	evaluator.On("Evaluate", mock.Anything, now).Return(nil)
+
+	s := &Server{
+		cfg: &config.Config{
+			Alert: &config.AlertConfig{
+				RulesFile:    "rules.json",
+				EvalInterval: 1,
+			},
+		},
+	}
+	s.Alerting(ctx, evaluator, getTicker)
+
+	ticker.ch <- now
+
+	select {
+	case <-evaluator.called:
+		// ok
+	case <-time.After(time.Second):
+		t.Error("rules were not evaluated within expected interval")
+	}
+}
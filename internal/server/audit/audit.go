@@ -69,10 +69,26 @@ func (ss *serviceSource) Log(ctx context.Context, data []byte) error {
 }
 
 // Entry is the structure for audit log entries.
+//
+// Agent is the certificate subject of the agent authenticated with mutual TLS.
 type Entry struct {
 	TS      int64    `json:"ts"`
 	Metrics []string `json:"metrics"`
 	IPAddr  string   `json:"ip_address"`
+	Agent   string   `json:"agent,omitempty"`
+}
+
+type agentKey struct{}
+
+// WithAgent returns a context carrying the identity of the agent which made the request.
+func WithAgent(ctx context.Context, agent string) context.Context {
+	return context.WithValue(ctx, agentKey{}, agent)
+}
+
+// AgentFromContext returns the identity of the agent stored by WithAgent.
+func AgentFromContext(ctx context.Context) string {
+	agent, _ := ctx.Value(agentKey{}).(string)
+	return agent
 }
 
 // Audit handles audit logging.
@@ -104,12 +120,13 @@ func New(cfg *config.AuditConfig) *Audit {
 	}
 }
 
-// LogMetrics logs the given metrics as audit entry.
+// LogMetrics logs the given metrics as audit entry, the agent identity is taken from the context.
 func (a *Audit) LogMetrics(ctx context.Context, metrics []string, ipAddr string) error {
 	auditEntry := &Entry{
 		TS:      time.Now().Unix(),
 		Metrics: metrics,
 		IPAddr:  ipAddr,
+		Agent:   AgentFromContext(ctx),
 	}
 	data, err := json.Marshal(auditEntry)
 	if err != nil {
@@ -2,12 +2,15 @@ package audit
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yogenyslav/ya-metrics/internal/config"
 )
 
@@ -148,3 +151,24 @@ func TestAudit_LogMetrics(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestAudit_LogMetrics_agent(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.AuditConfig{
+		File: t.TempDir() + "/test_audit.log",
+	}
+	audit := New(cfg)
+
+	ctx := WithAgent(context.Background(), "CN=agent-1")
+	err := audit.LogMetrics(ctx, []string{"metric1"}, "127.0.0.1")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(cfg.File)
+	require.NoError(t, err)
+
+	var entry Entry
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "CN=agent-1", entry.Agent)
+	assert.Equal(t, []string{"metric1"}, entry.Metrics)
+}
@@ -37,7 +37,7 @@ func (s *Server) Dumping(
 	ctx context.Context,
 	dumper middleware.Dumper,
 	newTicker TickerFactory,
-	gaugeRepo, counterRepo repository.Repo,
+	repos ...repository.Repo,
 ) {
 	if s.cfg.Dump.StoreInterval <= 0 {
 		return
@@ -45,7 +45,9 @@ func (s *Server) Dumping(
 
 	ticker := newTicker(time.Second * time.Duration(s.cfg.Dump.StoreInterval))
 
+	s.jobs.Add(1)
 	go func() {
+		defer s.jobs.Done()
 		defer ticker.Stop()
 
 		var err error
@@ -54,7 +56,7 @@ func (s *Server) Dumping(
 			case <-ctx.Done():
 				return
 			case <-ticker.C():
-				err = dumper.Dump(context.Background(), gaugeRepo, counterRepo)
+				err = dumper.Dump(context.Background(), repos...)
 				if err != nil {
 					log.Err(errs.Wrap(err)).Msg("dump metrics to file")
 				}
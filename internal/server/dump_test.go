@@ -27,8 +27,12 @@ type mockDumper struct {
 	called chan struct{}
 }
 
-func (d *mockDumper) Dump(ctx context.Context, gaugeRepo repository.Repo, counterRepo repository.Repo) error {
-	args := d.Called(ctx, gaugeRepo, counterRepo)
+func (d *mockDumper) Dump(ctx context.Context, repos ...repository.Repo) error {
+	callArgs := []any{ctx}
+	for _, repo := range repos {
+		callArgs = append(callArgs, repo)
+	}
+	args := d.Called(callArgs...)
 	d.called <- struct{}{}
 	return args.Error(0)
 }
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// ListAlerts handles requests to list pending, firing and recently resolved alerts.
+func (h *Handler) ListAlerts(w http.ResponseWriter, _ *http.Request) {
+	resp, err := json.Marshal(h.alerts.Alerts())
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/server/alert"
+	"github.com/yogenyslav/ya-metrics/internal/server/repository"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestHandler_ListAlerts(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	ms := new(mocks.MockMetricService)
+	ms.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{
+		{ID: "load", Type: model.Gauge, Value: pkg.Ptr(2.0)},
+	}, nil)
+
+	engine := alert.New([]alert.Rule{
+		{Name: "HighLoad", MetricID: "load", Type: model.Gauge, Op: alert.OpGreater, Threshold: 1},
+	}, ms, repository.NewSilenceInMemRepo(nil), nil)
+	require.NoError(t, engine.Evaluate(context.Background(), now))
+
+	h := NewHandler(ms, nil, nil, engine)
+	writer := httptest.NewRecorder()
+	h.ListAlerts(writer, httptest.NewRequest(http.MethodGet, "/alerts", nil))
+
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "application/json", writer.Header().Get("Content-Type"))
+
+	var got []alert.Alert
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &got))
+	assert.Equal(t, []alert.Alert{
+		{
+			Rule:     "HighLoad",
+			Group:    "HighLoad",
+			MetricID: "load",
+			Type:     model.Gauge,
+			State:    alert.StateFiring,
+			Value:    2,
+			ActiveAt: now,
+			FiredAt:  &now,
+		},
+	}, got)
+}
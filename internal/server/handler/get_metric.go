@@ -15,21 +15,30 @@ func (h *Handler) GetMetricRaw(w http.ResponseWriter, r *http.Request) {
 	metricType := r.PathValue(metricTypeParam)
 	metricID := r.PathValue(metricIDParam)
 
-	metric, err := h.ms.GetMetric(r.Context(), metricType, metricID)
+	metric, err := h.ms.GetMetric(r.Context(), metricType, metricID, nil)
 	if err != nil {
 		h.sendError(w, errs.Wrap(errs.ErrMetricNotFound, err.Error()))
 		return
 	}
 
-	var value string
-	if metricType == model.Gauge {
-		value = strconv.FormatFloat(*metric.Value, 'f', -1, 64)
-	} else {
-		value = strconv.FormatInt(*metric.Delta, 10)
+	var value []byte
+	switch metricType {
+	case model.Gauge:
+		value = strconv.AppendFloat(nil, *metric.Value, 'f', -1, 64)
+	case model.Counter:
+		value = strconv.AppendInt(nil, *metric.Delta, 10)
+	case model.Histogram:
+		value, err = json.Marshal(metric.Histogram)
+	case model.Summary:
+		value, err = json.Marshal(metric.Summary)
+	}
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(value))
+	w.Write(value)
 }
 
 // GetMetricJSON handles JSON metric retrieval requests.
@@ -47,17 +56,25 @@ func (h *Handler) GetMetricJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	metric, err := h.ms.GetMetric(r.Context(), req.Type, req.ID)
+	if !req.Labels.Valid() {
+		h.sendError(w, errs.Wrap(errs.ErrInvalidLabels))
+		return
+	}
+
+	metric, err := h.ms.GetMetric(r.Context(), req.Type, req.ID, req.Labels)
 	if err != nil {
 		h.sendError(w, errs.Wrap(errs.ErrMetricNotFound, err.Error()))
 		return
 	}
 
 	resp := model.MetricsDto{
-		ID:    metric.ID,
-		Type:  metric.Type,
-		Value: metric.Value,
-		Delta: metric.Delta,
+		ID:        metric.ID,
+		Type:      metric.Type,
+		Value:     metric.Value,
+		Delta:     metric.Delta,
+		Labels:    metric.Labels,
+		Histogram: metric.Histogram,
+		Summary:   metric.Summary,
 	}
 
 	respBody, err := json.Marshal(resp)
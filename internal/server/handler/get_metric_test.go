@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -36,7 +37,7 @@ func TestHandler_GetMetric(t *testing.T) {
 			name: "GetMetric gauge with existing metric",
 			ms: func() metricService {
 				m := new(mocks.MockMetricService)
-				m.On("GetMetric", mock.Anything, model.Gauge, "metric1").
+				m.On("GetMetric", mock.Anything, model.Gauge, "metric1", model.Labels(nil)).
 					Return(model.NewGaugeMetric("metric1").ToDto(), nil)
 				return m
 			},
@@ -56,7 +57,7 @@ func TestHandler_GetMetric(t *testing.T) {
 			name: "GetMetric counter with existing metric",
 			ms: func() metricService {
 				m := new(mocks.MockMetricService)
-				m.On("GetMetric", mock.Anything, model.Counter, "metric1").
+				m.On("GetMetric", mock.Anything, model.Counter, "metric1", model.Labels(nil)).
 					Return(model.NewCounterMetric("metric1").ToDto(), nil)
 				return m
 			},
@@ -76,7 +77,7 @@ func TestHandler_GetMetric(t *testing.T) {
 			name: "GetMetric with non-existing metric",
 			ms: func() metricService {
 				m := new(mocks.MockMetricService)
-				m.On("GetMetric", mock.Anything, model.Gauge, "non_existing_metric").
+				m.On("GetMetric", mock.Anything, model.Gauge, "non_existing_metric", model.Labels(nil)).
 					Return((*model.MetricsDto)(nil), errors.New("not found"))
 				return m
 			},
@@ -96,7 +97,7 @@ func TestHandler_GetMetric(t *testing.T) {
 			name: "GetMetric with invalid metric type",
 			ms: func() metricService {
 				m := new(mocks.MockMetricService)
-				m.On("GetMetric", mock.Anything, "invalid", "metric1").
+				m.On("GetMetric", mock.Anything, "invalid", "metric1", model.Labels(nil)).
 					Return((*model.MetricsDto)(nil), errors.New("invalid metric type"))
 				return m
 			},
@@ -116,7 +117,7 @@ func TestHandler_GetMetric(t *testing.T) {
 			name: "GetMetric with missing metric name",
 			ms: func() metricService {
 				m := new(mocks.MockMetricService)
-				m.On("GetMetric", mock.Anything, model.Gauge, "").
+				m.On("GetMetric", mock.Anything, model.Gauge, "", model.Labels(nil)).
 					Return((*model.MetricsDto)(nil), errors.New("metric ID is required"))
 				return m
 			},
@@ -141,7 +142,7 @@ func TestHandler_GetMetric(t *testing.T) {
 			t.Run(tt.name+" raw request", func(t *testing.T) {
 				t.Parallel()
 
-				h := NewHandler(tt.ms(), tt.db, tt.audit())
+				h := NewHandler(tt.ms(), tt.db, tt.audit(), nil)
 				writer := httptest.NewRecorder()
 
 				req := httptest.NewRequest(
@@ -163,7 +164,7 @@ func TestHandler_GetMetric(t *testing.T) {
 			t.Run(tt.name+"json request", func(t *testing.T) {
 				t.Parallel()
 
-				h := NewHandler(tt.ms(), tt.db, tt.audit())
+				h := NewHandler(tt.ms(), tt.db, tt.audit(), nil)
 				writer := httptest.NewRecorder()
 
 				data := model.MetricsDto{
@@ -209,12 +210,57 @@ func TestHandler_GetMetric(t *testing.T) {
 	}
 }
 
+func TestHandler_GetMetric_agentInstance(t *testing.T) {
+	t.Parallel()
+
+	svc := service.NewService(
+		repository.NewMetricInMemRepo(repository.StorageState[float64]{}),
+		repository.NewMetricInMemRepo(repository.StorageState[int64]{}),
+		repository.NewHistogramInMemRepo(nil),
+		repository.NewSummaryInMemRepo(nil),
+		nil,
+	)
+	audit := mocks.NewMockauditLogger(gomock.NewController(t))
+	audit.EXPECT().LogMetrics(gomock.Any(), []string{"Alloc"}, gomock.Any()).Return(nil)
+
+	router := chi.NewRouter()
+	NewHandler(svc, nil, audit, nil).RegisterRoutes(router)
+
+	body := []byte(`{"id":"Alloc","type":"gauge","value":12.5}`)
+	req := httptest.NewRequest(http.MethodPost, "/update/", bytes.NewReader(body))
+	req.Header.Set(model.InstanceHeader, "agent-1")
+	writer := httptest.NewRecorder()
+	router.ServeHTTP(writer, req)
+	require.Equal(t, http.StatusOK, writer.Code)
+
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/value/gauge/Alloc", nil))
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "12.5", writer.Body.String())
+
+	body = []byte(`{"id":"Alloc","type":"gauge"}`)
+	writer = httptest.NewRecorder()
+	router.ServeHTTP(writer, httptest.NewRequest(http.MethodPost, "/value/", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	var got model.MetricsDto
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &got))
+	assert.Equal(t, 12.5, *got.Value)
+	assert.Equal(t, model.Labels{model.InstanceLabel: "agent-1"}, got.Labels)
+}
+
 func BenchmarkHandler_GetMetric(b *testing.B) {
 	b.Run("in memory repo", func(b *testing.B) {
 		gaugeRepo := repository.NewMetricInMemRepo(repository.StorageState[float64]{})
 		counterRepo := repository.NewMetricInMemRepo(repository.StorageState[int64]{})
-		svc := service.NewService(gaugeRepo, counterRepo, nil)
-		h := NewHandler(svc, nil, nil)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramInMemRepo(nil),
+			repository.NewSummaryInMemRepo(nil),
+			nil,
+		)
+		h := NewHandler(svc, nil, nil, nil)
 
 		svc.UpdateMetric(b.Context(), &model.MetricsDto{
 			ID:    "gauge_metric",
@@ -251,8 +297,14 @@ func BenchmarkHandler_GetMetric(b *testing.B) {
 		mockDB := mocks.NewMockDB(gomock.NewController(b))
 		gaugeRepo := repository.NewMetricPostgresRepo[float64](mockDB)
 		counterRepo := repository.NewMetricPostgresRepo[int64](mockDB)
-		svc := service.NewService(gaugeRepo, counterRepo, nil)
-		h := NewHandler(svc, mockDB, nil)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramPostgresRepo(mockDB),
+			repository.NewSummaryPostgresRepo(mockDB),
+			nil,
+		)
+		h := NewHandler(svc, mockDB, nil, nil)
 
 		mockDB.EXPECT().
 			Exec(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -260,7 +312,7 @@ func BenchmarkHandler_GetMetric(b *testing.B) {
 			Times(2)
 
 		mockDB.EXPECT().
-			QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), "gauge_metric", model.Gauge).
+			QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), "gauge_metric", model.Gauge, gomock.Any()).
 			DoAndReturn(func(ctx any, dest any, query string, args ...any) error {
 				m := dest.(*model.MetricsDto)
 				m.ID = "gauge_metric"
@@ -272,7 +324,7 @@ func BenchmarkHandler_GetMetric(b *testing.B) {
 			AnyTimes()
 
 		mockDB.EXPECT().
-			QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), "counter_metric", model.Counter).
+			QueryRow(gomock.Any(), gomock.Any(), gomock.Any(), "counter_metric", model.Counter, gomock.Any()).
 			DoAndReturn(func(ctx any, dest any, query string, args ...any) error {
 				m := dest.(*model.MetricsDto)
 				m.ID = "counter_metric"
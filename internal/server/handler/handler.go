@@ -8,6 +8,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/server/alert"
 	"github.com/yogenyslav/ya-metrics/pkg/database"
 )
 
@@ -15,13 +16,16 @@ const (
 	metricTypeParam  = "metricType"
 	metricIDParam    = "metricID"
 	metricValueParam = "metricValue"
+	silenceIDParam   = "silenceID"
 )
 
 type metricService interface {
 	UpdateMetric(ctx context.Context, metric *model.MetricsDto) error
 	UpdateMetricsBatch(ctx context.Context, metrics []*model.MetricsDto) error
-	GetMetric(ctx context.Context, metricType, metricID string) (*model.MetricsDto, error)
+	GetMetric(ctx context.Context, metricType, metricID string, labels model.Labels) (*model.MetricsDto, error)
 	ListMetrics(ctx context.Context) ([]*model.MetricsDto, error)
+	ListMetricGroups(ctx context.Context) ([]*model.MetricGroup, error)
+	QueryRange(ctx context.Context, q *model.RangeQuery) (*model.Series, error)
 }
 
 //go:generate mockgen -destination=../../../tests/mocks/audit.go -package=mocks . auditLogger
@@ -29,37 +33,65 @@ type auditLogger interface {
 	LogMetrics(ctx context.Context, metrics []string, ipAddr string) error
 }
 
+type alertManager interface {
+	Alerts() []alert.Alert
+	CreateSilence(ctx context.Context, silence model.Silence) (model.Silence, error)
+	Silences(ctx context.Context) ([]model.Silence, error)
+	ExpireSilence(ctx context.Context, id string) error
+}
+
 // Handler serves HTTP requests.
 type Handler struct {
-	ms    metricService
-	db    database.DB
-	audit auditLogger
+	ms     metricService
+	db     database.DB
+	audit  auditLogger
+	alerts alertManager
 }
 
 // NewHandler creates new HTTP handler.
-func NewHandler(ms metricService, db database.DB, audit auditLogger) *Handler {
+func NewHandler(ms metricService, db database.DB, audit auditLogger, alerts alertManager) *Handler {
 	return &Handler{
-		ms:    ms,
-		db:    db,
-		audit: audit,
+		ms:     ms,
+		db:     db,
+		audit:  audit,
+		alerts: alerts,
 	}
 }
 
-// RegisterRoutes registers HTTP routes.
-func (h *Handler) RegisterRoutes(router chi.Router) {
+// RegisterRoutes registers HTTP routes, routes updating metrics are wrapped with updateMiddlewares.
+func (h *Handler) RegisterRoutes(router chi.Router, updateMiddlewares ...func(http.Handler) http.Handler) {
 	router.Get("/", h.ListMetrics)
 	router.Get("/ping", h.Ping)
+	router.Get("/metrics", h.PrometheusMetrics)
+	router.Get("/groups", h.ListMetricGroups)
+	router.Get("/alerts", h.ListAlerts)
+	router.Get("/silences", h.ListSilences)
+	router.Post("/silences/", h.CreateSilence)
+	router.Delete("/silences/{silenceID}", h.ExpireSilence)
 	router.Post("/value/", h.GetMetricJSON)
 	router.Get("/value/{metricType}/{metricID}", h.GetMetricRaw)
-	router.Post("/updates/", h.UpdateMetricsBatch)
-	router.Post(
-		"/update/",
-		h.UpdateMetricJSON,
-	)
-	router.Post(
-		"/update/{metricType}/{metricID}/{metricValue}",
-		h.UpdateMetricRaw,
-	)
+	router.Post("/query_range/", h.QueryRange)
+
+	router.Group(func(router chi.Router) {
+		router.Use(updateMiddlewares...)
+
+		router.Post("/updates/", h.UpdateMetricsBatch)
+		router.Post(
+			"/update/",
+			h.UpdateMetricJSON,
+		)
+		router.Post(
+			"/update/{metricType}/{metricID}/{metricValue}",
+			h.UpdateMetricRaw,
+		)
+	})
+}
+
+// withInstance labels the metric with the identity of the agent which sent the request.
+func withInstance(r *http.Request, m *model.MetricsDto) {
+	if instance := r.Header.Get(model.InstanceHeader); instance != "" {
+		m.Labels = m.Labels.With(model.InstanceLabel, instance)
+	}
 }
 
 func (h *Handler) sendError(w http.ResponseWriter, wrappedErr error) {
@@ -28,12 +28,18 @@ func ExampleHandler() {
 
 	gaugeRepo := repository.NewMetricInMemRepo[float64](nil)
 	counterRepo := repository.NewMetricInMemRepo[int64](nil)
-	metricService := service.NewService(gaugeRepo, counterRepo, uow)
+	metricService := service.NewService(
+		gaugeRepo,
+		counterRepo,
+		repository.NewHistogramInMemRepo(nil),
+		repository.NewSummaryInMemRepo(nil),
+		uow,
+	)
 
 	auditCfg := &config.AuditConfig{File: "audit.log"}
 	audit := audit.New(auditCfg)
 
-	h := NewHandler(metricService, nil, audit)
+	h := NewHandler(metricService, nil, audit, nil)
 
 	router := chi.NewRouter()
 	h.RegisterRoutes(router)
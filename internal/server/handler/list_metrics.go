@@ -25,3 +25,22 @@ func (h *Handler) ListMetrics(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(resp)
 }
+
+// ListMetricGroups handles requests to list all metric series grouped by metric name.
+func (h *Handler) ListMetricGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.ms.ListMetricGroups(r.Context())
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	resp, err := json.Marshal(groups)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
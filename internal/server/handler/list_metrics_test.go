@@ -13,6 +13,7 @@ import (
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/internal/server/repository"
 	"github.com/yogenyslav/ya-metrics/internal/server/service"
+	"github.com/yogenyslav/ya-metrics/pkg"
 	"github.com/yogenyslav/ya-metrics/pkg/database"
 	"github.com/yogenyslav/ya-metrics/tests/mocks"
 	gomock "go.uber.org/mock/gomock"
@@ -79,7 +80,7 @@ func TestHandler_ListMetrics(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			h := NewHandler(tt.ms, tt.db, tt.audit)
+			h := NewHandler(tt.ms, tt.db, tt.audit, nil)
 			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
 
 			h.ListMetrics(tt.writer, req)
@@ -92,12 +93,47 @@ func TestHandler_ListMetrics(t *testing.T) {
 	}
 }
 
+func TestHandler_ListMetricGroups(t *testing.T) {
+	t.Parallel()
+
+	groups := []*model.MetricGroup{
+		{
+			ID:   "PollCount",
+			Type: model.Counter,
+			Series: []*model.MetricsDto{
+				{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr[int64](1), Labels: model.Labels{"instance": "a"}},
+				{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr[int64](2), Labels: model.Labels{"instance": "b"}},
+			},
+		},
+	}
+
+	m := new(mocks.MockMetricService)
+	m.On("ListMetricGroups", mock.Anything).Return(groups, nil)
+
+	h := NewHandler(m, nil, nil, nil)
+	writer := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/groups", nil)
+
+	h.ListMetricGroups(writer, req)
+	assert.Equal(t, http.StatusOK, writer.Code)
+
+	wantBody, err := json.Marshal(groups)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantBody), writer.Body.String())
+}
+
 func BenchmarkHandler_ListMetrics(b *testing.B) {
 	b.Run("in memory repo", func(b *testing.B) {
 		gaugeRepo := repository.NewMetricInMemRepo(repository.StorageState[float64]{})
 		counterRepo := repository.NewMetricInMemRepo(repository.StorageState[int64]{})
-		svc := service.NewService(gaugeRepo, counterRepo, nil)
-		h := NewHandler(svc, nil, nil)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramInMemRepo(nil),
+			repository.NewSummaryInMemRepo(nil),
+			nil,
+		)
+		h := NewHandler(svc, nil, nil, nil)
 
 		svc.UpdateMetric(b.Context(), &model.MetricsDto{
 			ID:    "gauge_metric",
@@ -121,8 +157,14 @@ func BenchmarkHandler_ListMetrics(b *testing.B) {
 		mockDB := mocks.NewMockDB(gomock.NewController(b))
 		gaugeRepo := repository.NewMetricPostgresRepo[float64](mockDB)
 		counterRepo := repository.NewMetricPostgresRepo[int64](mockDB)
-		svc := service.NewService(gaugeRepo, counterRepo, nil)
-		h := NewHandler(svc, mockDB, nil)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramPostgresRepo(mockDB),
+			repository.NewSummaryPostgresRepo(mockDB),
+			nil,
+		)
+		h := NewHandler(svc, mockDB, nil, nil)
 
 		mockDB.EXPECT().
 			Exec(gomock.Any(), gomock.Any(), gomock.Any()).
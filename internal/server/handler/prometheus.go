@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"bytes"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusMetrics handles requests to export all metrics in Prometheus text exposition format.
+func (h *Handler) PrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	metrics, err := h.ms.ListMetrics(r.Context())
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", prometheusContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(encodePrometheus(metrics))
+}
+
+// encodePrometheus renders metrics grouped and sorted by exposed name, so that every name has a single TYPE line
+// and the output is stable between scrapes.
+//
+// IDs sanitized to the name of a series of another type are suffixed with the type,
+// series sanitized to the name and labels of an already written one are dropped.
+func encodePrometheus(metrics []*model.MetricsDto) []byte {
+	type series struct {
+		name string
+		m    *model.MetricsDto
+	}
+
+	// the first type in sort order keeps the sanitized name.
+	baseTypes := make(map[string]string)
+	supported := make([]*model.MetricsDto, 0, len(metrics))
+	for _, m := range metrics {
+		switch m.Type {
+		case model.Gauge, model.Counter, model.Histogram, model.Summary:
+		default:
+			continue
+		}
+		supported = append(supported, m)
+
+		base := sanitizeMetricName(m.ID)
+		if t, ok := baseTypes[base]; !ok || m.Type < t {
+			baseTypes[base] = m.Type
+		}
+	}
+
+	exposed := make([]series, 0, len(supported))
+	for _, m := range supported {
+		name := sanitizeMetricName(m.ID)
+		if baseTypes[name] != m.Type {
+			// the same name is used by several types, the exposition format requires unique names.
+			name += "_" + m.Type
+		}
+		exposed = append(exposed, series{name: name, m: m})
+	}
+	slices.SortFunc(exposed, func(a, b series) int {
+		if c := strings.Compare(a.name, b.name); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.m.Type, b.m.Type); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.m.Labels.Key(), b.m.Labels.Key()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.m.ID, b.m.ID)
+	})
+
+	var (
+		buf      bytes.Buffer
+		prevName string
+		prevKey  string
+	)
+	nameTypes := make(map[string]string, len(exposed))
+
+	for _, s := range exposed {
+		m := s.m
+		name := s.name
+		if t, ok := nameTypes[name]; ok && t != m.Type {
+			// a suffixed name is taken by a series of another type.
+			continue
+		}
+		nameTypes[name] = m.Type
+
+		key := model.SeriesKey(name, m.Labels)
+		if key == prevKey {
+			continue
+		}
+		prevKey = key
+
+		if name != prevName {
+			buf.WriteString("# TYPE " + name + " " + m.Type + "\n")
+			prevName = name
+		}
+
+		switch m.Type {
+		case model.Gauge:
+			writePrometheusSample(&buf, name, m.Labels, strconv.FormatFloat(*m.Value, 'g', -1, 64))
+		case model.Counter:
+			writePrometheusSample(&buf, name, m.Labels, strconv.FormatInt(*m.Delta, 10))
+		case model.Histogram:
+			writePrometheusHistogram(&buf, name, m.Labels, m.Histogram)
+		case model.Summary:
+			writePrometheusSummary(&buf, name, m.Labels, m.Summary)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func writePrometheusSample(buf *bytes.Buffer, name string, labels model.Labels, value string) {
+	buf.WriteString(name)
+	writePrometheusLabels(buf, labels)
+	buf.WriteString(" " + value + "\n")
+}
+
+// writePrometheusHistogram renders cumulative buckets followed by the sum and the count of observations.
+func writePrometheusHistogram(buf *bytes.Buffer, name string, labels model.Labels, h *model.HistogramData) {
+	var cumulative int64
+	for i, count := range h.Counts {
+		cumulative += count
+
+		le := "+Inf"
+		if i < len(h.Bounds) {
+			le = strconv.FormatFloat(h.Bounds[i], 'g', -1, 64)
+		}
+		writePrometheusSample(buf, name+"_bucket", labels.With("le", le), strconv.FormatInt(cumulative, 10))
+	}
+
+	writePrometheusSample(buf, name+"_sum", labels, strconv.FormatFloat(h.Sum, 'g', -1, 64))
+	writePrometheusSample(buf, name+"_count", labels, strconv.FormatInt(h.Count, 10))
+}
+
+// writePrometheusSummary renders estimated quantiles followed by the sum and the count of observations.
+func writePrometheusSummary(buf *bytes.Buffer, name string, labels model.Labels, s *model.SummaryData) {
+	for _, q := range slices.Sorted(maps.Keys(s.Quantiles)) {
+		value := strconv.FormatFloat(s.Quantiles[q], 'g', -1, 64)
+		writePrometheusSample(buf, name, labels.With("quantile", q), value)
+	}
+
+	writePrometheusSample(buf, name+"_sum", labels, strconv.FormatFloat(s.Sum, 'g', -1, 64))
+	writePrometheusSample(buf, name+"_count", labels, strconv.FormatInt(s.Count, 10))
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writePrometheusLabels(buf *bytes.Buffer, labels model.Labels) {
+	if len(labels) == 0 {
+		return
+	}
+
+	buf.WriteByte('{')
+	for i, name := range slices.Sorted(maps.Keys(labels)) {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(name + `="` + labelValueEscaper.Replace(labels[name]) + `"`)
+	}
+	buf.WriteByte('}')
+}
+
+// sanitizeMetricName makes the name match [a-zA-Z_:][a-zA-Z0-9_:]* replacing invalid characters with '_'.
+func sanitizeMetricName(name string) string {
+	if name == "" {
+		return "_"
+	}
+
+	var b strings.Builder
+	b.Grow(len(name) + 1)
+
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestHandler_PrometheusMetrics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ms       func() metricService
+		wantCode int
+		wantBody string
+	}{
+		{
+			name: "PrometheusMetrics success",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{
+					{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr[int64](5)},
+					{ID: "CPUutilization0", Type: model.Gauge, Value: pkg.Ptr(12.5)},
+					{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1024.0)},
+				}, nil)
+				return m
+			},
+			wantCode: http.StatusOK,
+			wantBody: "# TYPE Alloc gauge\nAlloc 1024\n" +
+				"# TYPE CPUutilization0 gauge\nCPUutilization0 12.5\n" +
+				"# TYPE PollCount counter\nPollCount 5\n",
+		},
+		{
+			name: "PrometheusMetrics empty",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{}, nil)
+				return m
+			},
+			wantCode: http.StatusOK,
+			wantBody: "",
+		},
+		{
+			name: "PrometheusMetrics error",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto(nil), errors.New("db error"))
+				return m
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(tt.ms(), nil, nil, nil)
+			writer := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+
+			h.PrometheusMetrics(writer, req)
+			assert.Equal(t, tt.wantCode, writer.Code)
+
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, prometheusContentType, writer.Header().Get("Content-Type"))
+				assert.Equal(t, tt.wantBody, writer.Body.String())
+			}
+		})
+	}
+}
+
+func Test_encodePrometheus(t *testing.T) {
+	t.Parallel()
+
+	metrics := []*model.MetricsDto{
+		{ID: "requests", Type: model.Counter, Delta: pkg.Ptr[int64](3)},
+		{ID: "requests", Type: model.Gauge, Value: pkg.Ptr(1.5)},
+	}
+
+	want := "# TYPE requests counter\nrequests 3\n" +
+		"# TYPE requests_gauge gauge\nrequests_gauge 1.5\n"
+	assert.Equal(t, want, string(encodePrometheus(metrics)))
+}
+
+func Test_encodePrometheus_labels(t *testing.T) {
+	t.Parallel()
+
+	metrics := []*model.MetricsDto{
+		{ID: "CPUutilization", Type: model.Gauge, Value: pkg.Ptr(20.0), Labels: model.Labels{"cpu": "1"}},
+		{ID: "CPUutilization", Type: model.Gauge, Value: pkg.Ptr(10.0), Labels: model.Labels{"cpu": "0"}},
+		{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.0), Labels: model.Labels{"path": `C:\tmp "x"`}},
+	}
+
+	want := "# TYPE Alloc gauge\n" + `Alloc{path="C:\\tmp \"x\""} 1` + "\n" +
+		"# TYPE CPUutilization gauge\n" +
+		`CPUutilization{cpu="0"} 10` + "\n" +
+		`CPUutilization{cpu="1"} 20` + "\n"
+	assert.Equal(t, want, string(encodePrometheus(metrics)))
+}
+
+func Test_encodePrometheus_histogram(t *testing.T) {
+	t.Parallel()
+
+	metrics := []*model.MetricsDto{
+		{
+			ID:     "latency",
+			Type:   model.Histogram,
+			Labels: model.Labels{"path": "/"},
+			Histogram: &model.HistogramData{
+				Bounds: []float64{0.1, 1},
+				Counts: []int64{3, 2, 1},
+				Count:  6,
+				Sum:    2.5,
+			},
+		},
+	}
+
+	want := "# TYPE latency histogram\n" +
+		`latency_bucket{le="0.1",path="/"} 3` + "\n" +
+		`latency_bucket{le="1",path="/"} 5` + "\n" +
+		`latency_bucket{le="+Inf",path="/"} 6` + "\n" +
+		`latency_sum{path="/"} 2.5` + "\n" +
+		`latency_count{path="/"} 6` + "\n"
+	assert.Equal(t, want, string(encodePrometheus(metrics)))
+}
+
+func Test_encodePrometheus_summary(t *testing.T) {
+	t.Parallel()
+
+	metrics := []*model.MetricsDto{
+		{
+			ID:   "request_duration",
+			Type: model.Summary,
+			Summary: &model.SummaryData{
+				Count:     4,
+				Sum:       1.2,
+				Quantiles: map[string]float64{"0.5": 0.2, "0.9": 0.5, "0.99": 0.6},
+			},
+		},
+	}
+
+	want := "# TYPE request_duration summary\n" +
+		`request_duration{quantile="0.5"} 0.2` + "\n" +
+		`request_duration{quantile="0.9"} 0.5` + "\n" +
+		`request_duration{quantile="0.99"} 0.6` + "\n" +
+		"request_duration_sum 1.2\n" +
+		"request_duration_count 4\n"
+	assert.Equal(t, want, string(encodePrometheus(metrics)))
+}
+
+func Test_sanitizeMetricName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "valid name", in: "CPUutilization0", want: "CPUutilization0"},
+		{name: "leading digit", in: "0cpu", want: "_0cpu"},
+		{name: "invalid characters", in: "heap.alloc-bytes", want: "heap_alloc_bytes"},
+		{name: "colon allowed", in: "job:rate", want: "job:rate"},
+		{name: "empty", in: "", want: "_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, sanitizeMetricName(tt.in))
+		})
+	}
+}
+
+func Test_encodePrometheus_sanitizedCollisions(t *testing.T) {
+	t.Parallel()
+
+	metrics := []*model.MetricsDto{
+		{ID: "disk.used", Type: model.Gauge, Value: pkg.Ptr(1.0), Labels: model.Labels{"mount": "/"}},
+		{ID: "disk_total", Type: model.Gauge, Value: pkg.Ptr(3.0)},
+		{ID: "disk-used", Type: model.Gauge, Value: pkg.Ptr(2.0), Labels: model.Labels{"mount": "/data"}},
+		{ID: "disk used", Type: model.Counter, Delta: pkg.Ptr[int64](4)},
+		{ID: "disk_used", Type: model.Gauge, Value: pkg.Ptr(5.0), Labels: model.Labels{"mount": "/"}},
+	}
+
+	want := "# TYPE disk_total gauge\ndisk_total 3\n" +
+		"# TYPE disk_used counter\ndisk_used 4\n" +
+		"# TYPE disk_used_gauge gauge\n" +
+		`disk_used_gauge{mount="/"} 1` + "\n" +
+		`disk_used_gauge{mount="/data"} 2` + "\n"
+	assert.Equal(t, want, string(encodePrometheus(metrics)))
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// QueryRange handles JSON requests for metric values sampled at a fixed step.
+func (h *Handler) QueryRange(w http.ResponseWriter, r *http.Request) {
+	var req model.RangeQuery
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.sendError(w, errs.Wrap(errs.ErrInvalidJSON, err.Error()))
+		return
+	}
+
+	if req.ID == "" {
+		h.sendError(w, errs.Wrap(errs.ErrNoMetricID))
+		return
+	}
+
+	series, err := h.ms.QueryRange(r.Context(), &req)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	respBody, err := json.Marshal(series)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
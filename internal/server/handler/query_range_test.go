@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestHandler_QueryRange(t *testing.T) {
+	t.Parallel()
+
+	query := &model.RangeQuery{ID: "metric1", Type: model.Gauge, Start: 100, End: 120, Step: 10}
+	series := &model.Series{
+		ID:   "metric1",
+		Type: model.Gauge,
+		Points: []*model.Point{
+			{Timestamp: 100, Value: pkg.Ptr(1.5)},
+			{Timestamp: 110, Value: pkg.Ptr(2.5)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		ms       func() metricService
+		body     []byte
+		wantCode int
+		wantBody *model.Series
+	}{
+		{
+			name: "QueryRange success",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("QueryRange", mock.Anything, query).Return(series, nil)
+				return m
+			},
+			body: func() []byte {
+				data, _ := json.Marshal(query)
+				return data
+			}(),
+			wantCode: http.StatusOK,
+			wantBody: series,
+		},
+		{
+			name: "QueryRange with invalid range",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("QueryRange", mock.Anything, query).
+					Return((*model.Series)(nil), errs.Wrap(errs.ErrInvalidTimeRange))
+				return m
+			},
+			body: func() []byte {
+				data, _ := json.Marshal(query)
+				return data
+			}(),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "QueryRange with history disabled",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("QueryRange", mock.Anything, query).
+					Return((*model.Series)(nil), errs.Wrap(errs.ErrHistoryDisabled))
+				return m
+			},
+			body: func() []byte {
+				data, _ := json.Marshal(query)
+				return data
+			}(),
+			wantCode: http.StatusNotImplemented,
+		},
+		{
+			name: "QueryRange with unknown function",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("QueryRange", mock.Anything, &model.RangeQuery{
+					ID: "PollCount", Type: model.Counter, Start: 100, End: 120, Step: 10, Func: "avg",
+				}).Return((*model.Series)(nil), errs.Wrap(errs.ErrInvalidQueryFunc))
+				return m
+			},
+			body:     []byte(`{"id":"PollCount","type":"counter","start":100,"end":120,"step":10,"func":"avg"}`),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "QueryRange without metric ID",
+			ms: func() metricService {
+				return new(mocks.MockMetricService)
+			},
+			body:     []byte(`{"type":"gauge","start":100,"end":120,"step":10}`),
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name: "QueryRange with invalid JSON",
+			ms: func() metricService {
+				return new(mocks.MockMetricService)
+			},
+			body:     []byte(`{"id":`),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := NewHandler(tt.ms(), nil, nil, nil)
+			writer := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/query_range/", bytes.NewReader(tt.body))
+
+			h.QueryRange(writer, req)
+			assert.Equal(t, tt.wantCode, writer.Code)
+
+			if tt.wantBody != nil {
+				wantStr, err := json.Marshal(tt.wantBody)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantStr), writer.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// ListSilences handles requests to list active, pending and expired silences.
+func (h *Handler) ListSilences(w http.ResponseWriter, r *http.Request) {
+	silences, err := h.alerts.Silences(r.Context())
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	resp, err := json.Marshal(silences)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp)
+}
+
+// CreateSilence handles requests to create a silence, the created silence is returned with its ID.
+func (h *Handler) CreateSilence(w http.ResponseWriter, r *http.Request) {
+	var req model.Silence
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.sendError(w, errs.Wrap(errs.ErrInvalidJSON, err.Error()))
+		return
+	}
+
+	silence, err := h.alerts.CreateSilence(r.Context(), req)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	resp, err := json.Marshal(silence)
+	if err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(resp)
+}
+
+// ExpireSilence handles requests to end a silence now.
+func (h *Handler) ExpireSilence(w http.ResponseWriter, r *http.Request) {
+	if err := h.alerts.ExpireSilence(r.Context(), chi.URLParam(r, silenceIDParam)); err != nil {
+		h.sendError(w, errs.Wrap(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
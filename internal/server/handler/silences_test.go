@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/server/alert"
+	"github.com/yogenyslav/ya-metrics/internal/server/repository"
+)
+
+func TestHandler_Silences(t *testing.T) {
+	t.Parallel()
+
+	router := chi.NewRouter()
+	NewHandler(nil, nil, nil, alert.New(nil, nil, repository.NewSilenceInMemRepo(nil), nil)).RegisterRoutes(router)
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		writer := httptest.NewRecorder()
+		router.ServeHTTP(writer, httptest.NewRequest(method, url, strings.NewReader(body)))
+		return writer
+	}
+
+	endsAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	writer := do(http.MethodPost, "/silences/", `{"metric":"load","labels":{"dc":"east"},"ends_at":"`+endsAt+`"}`)
+	require.Equal(t, http.StatusCreated, writer.Code)
+
+	var created model.Silence
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, model.Labels{"dc": "east"}, created.Labels)
+	assert.True(t, created.Active(time.Now()))
+
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Create invalid JSON",
+			method:   http.MethodPost,
+			url:      "/silences/",
+			body:     `{"metric":`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Create silence without matcher",
+			method:   http.MethodPost,
+			url:      "/silences/",
+			body:     `{"ends_at":"` + endsAt + `"}`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Expire unknown silence",
+			method:   http.MethodDelete,
+			url:      "/silences/unknown",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Expire silence",
+			method:   http.MethodDelete,
+			url:      "/silences/" + created.ID,
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.wantCode, do(tt.method, tt.url, tt.body).Code, tt.name)
+	}
+
+	writer = do(http.MethodGet, "/silences", "")
+	require.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "application/json", writer.Header().Get("Content-Type"))
+
+	var silences []model.Silence
+	require.NoError(t, json.Unmarshal(writer.Body.Bytes(), &silences))
+	require.Len(t, silences, 1)
+	assert.Equal(t, created.ID, silences[0].ID)
+	assert.False(t, silences[0].Active(time.Now()))
+}
@@ -9,8 +9,15 @@ import (
 var errStatusCodes = map[error]int{
 	errs.ErrInvalidMetricType:   http.StatusBadRequest,
 	errs.ErrInvalidMetricValue:  http.StatusBadRequest,
+	errs.ErrInvalidTimeRange:    http.StatusBadRequest,
+	errs.ErrInvalidLabels:       http.StatusBadRequest,
+	errs.ErrInvalidQueryFunc:    http.StatusBadRequest,
+	errs.ErrInvalidSilence:      http.StatusBadRequest,
 	errs.ErrNoMetricID:          http.StatusNotFound,
 	errs.ErrMetricNotFound:      http.StatusNotFound,
+	errs.ErrSilenceNotFound:     http.StatusNotFound,
+	errs.ErrBucketsMismatch:     http.StatusConflict,
 	errs.ErrInvalidJSON:         http.StatusUnprocessableEntity,
 	errs.ErrDatabaseUnavailable: http.StatusInternalServerError,
+	errs.ErrHistoryDisabled:     http.StatusNotImplemented,
 }
@@ -25,9 +25,10 @@ func (h *Handler) UpdateMetricRaw(w http.ResponseWriter, r *http.Request) {
 		ID:   metricID,
 		Type: metricType,
 	}
+	withInstance(r, m)
 
 	switch metricType {
-	case model.Gauge:
+	case model.Gauge, model.Summary:
 		gaugeValue, err := strconv.ParseFloat(metricValueRaw, 64)
 		if err != nil {
 			h.sendError(w, errs.Wrap(errs.ErrInvalidMetricValue, err.Error()))
@@ -77,6 +78,12 @@ func (h *Handler) UpdateMetricJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !req.Labels.Valid() {
+		h.sendError(w, errs.Wrap(errs.ErrInvalidLabels))
+		return
+	}
+	withInstance(r, &req)
+
 	if err := h.ms.UpdateMetric(r.Context(), &req); err != nil {
 		h.sendError(w, errs.Wrap(err))
 		return
@@ -112,6 +119,11 @@ func (h *Handler) UpdateMetricsBatch(w http.ResponseWriter, r *http.Request) {
 			h.sendError(w, errs.Wrap(errs.ErrNoMetricID))
 			return
 		}
+		if !m.Labels.Valid() {
+			h.sendError(w, errs.Wrap(errs.ErrInvalidLabels))
+			return
+		}
+		withInstance(r, m)
 		metricsNames = append(metricsNames, m.ID)
 	}
 
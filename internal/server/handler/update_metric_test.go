@@ -136,7 +136,7 @@ func TestHandler_UpdateMetric(t *testing.T) {
 				t.Run(tt.name+" raw request", func(t *testing.T) {
 					t.Parallel()
 
-					h := NewHandler(tt.ms(), tt.db, tt.audit())
+					h := NewHandler(tt.ms(), tt.db, tt.audit(), nil)
 
 					writer := httptest.NewRecorder()
 					req := httptest.NewRequest(
@@ -156,7 +156,7 @@ func TestHandler_UpdateMetric(t *testing.T) {
 				t.Run(tt.name+" json request", func(t *testing.T) {
 					t.Parallel()
 
-					h := NewHandler(tt.ms(), tt.db, tt.audit())
+					h := NewHandler(tt.ms(), tt.db, tt.audit(), nil)
 
 					data := model.MetricsDto{
 						Type: tt.metricType,
@@ -266,6 +266,55 @@ func TestHandler_UpdateMetricsBatch(t *testing.T) {
 			},
 			wantCode: http.StatusBadRequest,
 		},
+		{
+			name: "UpdateMetricsBatch with labels",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("UpdateMetricsBatch", mock.Anything, []*model.MetricsDto{
+					{
+						ID:     "CPUutilization",
+						Type:   model.Gauge,
+						Value:  pkg.Ptr(12.5),
+						Labels: model.Labels{"cpu": "0"},
+					},
+				}).Return(nil)
+				return m
+			},
+			audit: func() auditLogger {
+				m := mocks.NewMockauditLogger(gomock.NewController(t))
+				m.EXPECT().
+					LogMetrics(gomock.Any(), []string{"CPUutilization"}, gomock.Any()).
+					Return(nil)
+				return m
+			},
+			metrics: []model.MetricsDto{
+				{
+					ID:     "CPUutilization",
+					Type:   model.Gauge,
+					Value:  pkg.Ptr(12.5),
+					Labels: model.Labels{"cpu": "0"},
+				},
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name: "UpdateMetricsBatch with invalid label name",
+			ms: func() metricService {
+				return new(mocks.MockMetricService)
+			},
+			audit: func() auditLogger {
+				return mocks.NewMockauditLogger(gomock.NewController(t))
+			},
+			metrics: []model.MetricsDto{
+				{
+					ID:     "CPUutilization",
+					Type:   model.Gauge,
+					Value:  pkg.Ptr(12.5),
+					Labels: model.Labels{"0cpu": "0"},
+				},
+			},
+			wantCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -273,7 +322,7 @@ func TestHandler_UpdateMetricsBatch(t *testing.T) {
 			tt.name, func(t *testing.T) {
 				t.Parallel()
 
-				h := NewHandler(tt.ms(), nil, tt.audit())
+				h := NewHandler(tt.ms(), nil, tt.audit(), nil)
 
 				body, err := json.Marshal(tt.metrics)
 				require.NoError(t, err)
@@ -293,6 +342,31 @@ func TestHandler_UpdateMetricsBatch(t *testing.T) {
 	}
 }
 
+func TestHandler_UpdateMetricJSON_instance(t *testing.T) {
+	t.Parallel()
+
+	ms := new(mocks.MockMetricService)
+	ms.On("UpdateMetric", mock.Anything, &model.MetricsDto{
+		ID:     "PollCount",
+		Type:   model.Counter,
+		Delta:  pkg.Ptr[int64](1),
+		Labels: model.Labels{"cpu": "0", model.InstanceLabel: "agent-1"},
+	}).Return(nil)
+
+	audit := mocks.NewMockauditLogger(gomock.NewController(t))
+	audit.EXPECT().LogMetrics(gomock.Any(), []string{"PollCount"}, gomock.Any()).Return(nil)
+
+	h := NewHandler(ms, nil, audit, nil)
+
+	body := []byte(`{"id":"PollCount","type":"counter","delta":1,"labels":{"cpu":"0"}}`)
+	writer := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/update/", bytes.NewReader(body))
+	req.Header.Set(model.InstanceHeader, "agent-1")
+
+	h.UpdateMetricJSON(writer, req)
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
+
 func BenchmarkHandler_UpdateMetric(b *testing.B) {
 	mockAudit := mocks.NewMockauditLogger(gomock.NewController(b))
 	mockAudit.EXPECT().
@@ -303,8 +377,14 @@ func BenchmarkHandler_UpdateMetric(b *testing.B) {
 		gaugeRepo := repository.NewMetricInMemRepo(repository.StorageState[float64]{})
 		counterRepo := repository.NewMetricInMemRepo(repository.StorageState[int64]{})
 		uow := mocks.NewMockUnitOfWork(gomock.NewController(b))
-		svc := service.NewService(gaugeRepo, counterRepo, uow)
-		h := NewHandler(svc, nil, mockAudit)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramInMemRepo(nil),
+			repository.NewSummaryInMemRepo(nil),
+			uow,
+		)
+		h := NewHandler(svc, nil, mockAudit, nil)
 
 		b.ResetTimer()
 
@@ -325,11 +405,17 @@ func BenchmarkHandler_UpdateMetric(b *testing.B) {
 		gaugeRepo := repository.NewMetricPostgresRepo[float64](mockDB)
 		counterRepo := repository.NewMetricPostgresRepo[int64](mockDB)
 		uow := mocks.NewMockUnitOfWork(gomock.NewController(b))
-		svc := service.NewService(gaugeRepo, counterRepo, uow)
-		h := NewHandler(svc, nil, mockAudit)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramPostgresRepo(mockDB),
+			repository.NewSummaryPostgresRepo(mockDB),
+			uow,
+		)
+		h := NewHandler(svc, nil, mockAudit, nil)
 
 		mockDB.EXPECT().
-			Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(int64(1), nil).
 			AnyTimes()
 
@@ -358,8 +444,14 @@ func BenchmarkHandler_UpdateMetricsBatch(b *testing.B) {
 		gaugeRepo := repository.NewMetricInMemRepo(repository.StorageState[float64]{})
 		counterRepo := repository.NewMetricInMemRepo(repository.StorageState[int64]{})
 		uow := mocks.NewMockUnitOfWork(gomock.NewController(b))
-		svc := service.NewService(gaugeRepo, counterRepo, uow)
-		h := NewHandler(svc, nil, mockAudit)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramInMemRepo(nil),
+			repository.NewSummaryInMemRepo(nil),
+			uow,
+		)
+		h := NewHandler(svc, nil, mockAudit, nil)
 
 		uow.EXPECT().
 			WithTx(gomock.Any(), gomock.Any()).
@@ -381,11 +473,17 @@ func BenchmarkHandler_UpdateMetricsBatch(b *testing.B) {
 		gaugeRepo := repository.NewMetricPostgresRepo[float64](mockDB)
 		counterRepo := repository.NewMetricPostgresRepo[int64](mockDB)
 		uow := mocks.NewMockUnitOfWork(gomock.NewController(b))
-		svc := service.NewService(gaugeRepo, counterRepo, uow)
-		h := NewHandler(svc, nil, mockAudit)
+		svc := service.NewService(
+			gaugeRepo,
+			counterRepo,
+			repository.NewHistogramPostgresRepo(mockDB),
+			repository.NewSummaryPostgresRepo(mockDB),
+			uow,
+		)
+		h := NewHandler(svc, nil, mockAudit, nil)
 
 		mockDB.EXPECT().
-			Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(int64(1), nil).
 			AnyTimes()
 
@@ -49,7 +49,9 @@ func newCompressResponseWriter(w http.ResponseWriter, compressionType string) *c
 
 	switch compressionType {
 	case GzipCompression:
-		compression = gzipWriterPool.Get().(*gzip.Writer)
+		gz := gzipWriterPool.Get().(*gzip.Writer)
+		gz.Reset(w)
+		compression = gz
 	default:
 		compression = nopCloser{Writer: w}
 	}
@@ -68,7 +70,6 @@ func (c *compressionResponseWriter) Header() http.Header {
 
 // Write implements http.ResponseWriter Write method.
 func (c *compressionResponseWriter) Write(b []byte) (int, error) {
-	c.compression.Reset(c.w)
 	return c.compression.Write(b)
 }
 
@@ -80,10 +81,13 @@ func (c *compressionResponseWriter) WriteHeader(statusCode int) {
 	c.w.WriteHeader(statusCode)
 }
 
-// Close the compression writer.
+// Close the compression writer, gzip writers are returned to the pool.
 func (c *compressionResponseWriter) Close() error {
-	defer gzipWriterPool.Put(c.compression)
-	return c.compression.Close()
+	err := c.compression.Close()
+	if gz, ok := c.compression.(*gzip.Writer); ok {
+		gzipWriterPool.Put(gz)
+	}
+	return err
 }
 
 // compressionReader is a wrapper around io.ReadCloser to handle request decompression.
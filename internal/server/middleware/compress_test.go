@@ -107,3 +107,26 @@ func TestWithCompression(t *testing.T) {
 		})
 	}
 }
+
+func TestWithCompression_multipleWrites(t *testing.T) {
+	t.Parallel()
+
+	h := WithCompression(GzipCompression)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("first "))
+		w.Write([]byte("second"))
+	}))
+
+	for range 2 {
+		req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+		req.Header.Set("Accept-Encoding", GzipCompression)
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+
+		gz, err := gzip.NewReader(recorder.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, "first second", string(body))
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
+)
+
+// Decryptor is an interface for decrypting request bodies.
+type Decryptor interface {
+	Decrypt(data []byte) ([]byte, error)
+}
+
+// WithDecryption is a middleware that decrypts request bodies marked as encrypted.
+//
+// It has to run before WithCompression, agents compress the payload before encrypting it.
+// Plain requests pass through, encrypted requests are rejected when no decryptor is configured.
+func WithDecryption(d Decryptor) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme := r.Header.Get(secure.EncryptionHeader)
+			if scheme == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if d == nil || scheme != secure.EncryptionScheme {
+				http.Error(w, "unsupported encryption", http.StatusBadRequest)
+				return
+			}
+
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusInternalServerError)
+				return
+			}
+			r.Body.Close()
+
+			plain, err := d.Decrypt(data)
+			if err != nil {
+				http.Error(w, "failed to decrypt request body", http.StatusBadRequest)
+				return
+			}
+
+			r.Header.Del(secure.EncryptionHeader)
+			r.Body = io.NopCloser(bytes.NewReader(plain))
+			r.ContentLength = int64(len(plain))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
+)
+
+func TestWithDecryption(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	enc := secure.NewEncryptor(&key.PublicKey)
+
+	data := "test data"
+	gzipData := func() []byte {
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		w.Write([]byte(data))
+		w.Close()
+		return buf.Bytes()
+	}
+	encrypt := func(data []byte) []byte {
+		encrypted, err := enc.Encrypt(data)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	tests := []struct {
+		name      string
+		decryptor Decryptor
+		body      []byte
+		headers   http.Header
+		wantCode  int
+		wantBody  string
+	}{
+		{
+			name:      "Plain request",
+			decryptor: secure.NewDecryptor(key),
+			body:      []byte(data),
+			wantCode:  http.StatusOK,
+			wantBody:  data,
+		},
+		{
+			name:      "Encrypted request",
+			decryptor: secure.NewDecryptor(key),
+			body:      encrypt([]byte(data)),
+			headers: http.Header{
+				secure.EncryptionHeader: []string{secure.EncryptionScheme},
+			},
+			wantCode: http.StatusOK,
+			wantBody: data,
+		},
+		{
+			name:      "Encrypted gzip request",
+			decryptor: secure.NewDecryptor(key),
+			body:      encrypt(gzipData()),
+			headers: http.Header{
+				secure.EncryptionHeader: []string{secure.EncryptionScheme},
+				"Content-Encoding":      []string{GzipCompression},
+			},
+			wantCode: http.StatusOK,
+			wantBody: data,
+		},
+		{
+			name:      "Corrupted request",
+			decryptor: secure.NewDecryptor(key),
+			body:      []byte(data),
+			headers: http.Header{
+				secure.EncryptionHeader: []string{secure.EncryptionScheme},
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "Encrypted request without key",
+			body: encrypt([]byte(data)),
+			headers: http.Header{
+				secure.EncryptionHeader: []string{secure.EncryptionScheme},
+			},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := WithDecryption(tt.decryptor)(WithCompression(GzipCompression)(testCompressionHandler(t)))
+			req := httptest.NewRequest(http.MethodPost, "/test", bytes.NewReader(tt.body))
+			if tt.headers != nil {
+				req.Header = tt.headers
+			}
+
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantCode, recorder.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.wantBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -3,30 +3,65 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/rs/zerolog/log"
 	"github.com/yogenyslav/ya-metrics/internal/server/repository"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"google.golang.org/grpc"
 )
 
 // Dumper is an interface for dumping metrics to file.
 type Dumper interface {
-	Dump(ctx context.Context, gaugeRepo repository.Repo, counterRepo repository.Repo) error
+	Dump(ctx context.Context, repos ...repository.Repo) error
 }
 
 // WithFileDumper returns a dumping middleware if intervalSec is <= 0.
-func WithFileDumper(d Dumper, intervalSec int, gaugeRepo, counterRepo repository.Repo) Middleware {
+func WithFileDumper(d Dumper, intervalSec int, repos ...repository.Repo) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			next.ServeHTTP(w, r)
-			if intervalSec > 0 {
-				return
-			}
-
-			err := d.Dump(r.Context(), gaugeRepo, counterRepo)
-			if err != nil {
-				log.Ctx(r.Context()).Err(errs.Wrap(err)).Msg("dump metrics to file")
+			if intervalSec <= 0 {
+				dump(r.Context(), d, repos...)
 			}
 		})
 	}
 }
+
+// UnaryFileDumper is the gRPC counterpart of WithFileDumper, it dumps after successful calls of the given methods.
+func UnaryFileDumper(
+	d Dumper,
+	intervalSec int,
+	methods []string,
+	repos ...repository.Repo,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil && intervalSec <= 0 && slices.Contains(methods, info.FullMethod) {
+			dump(ctx, d, repos...)
+		}
+		return resp, err
+	}
+}
+
+// StreamFileDumper is the gRPC counterpart of WithFileDumper for streams of the given methods.
+func StreamFileDumper(
+	d Dumper,
+	intervalSec int,
+	methods []string,
+	repos ...repository.Repo,
+) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err == nil && intervalSec <= 0 && slices.Contains(methods, info.FullMethod) {
+			dump(ss.Context(), d, repos...)
+		}
+		return err
+	}
+}
+
+func dump(ctx context.Context, d Dumper, repos ...repository.Repo) {
+	if err := d.Dump(ctx, repos...); err != nil {
+		log.Ctx(ctx).Err(errs.Wrap(err)).Msg("dump metrics to file")
+	}
+}
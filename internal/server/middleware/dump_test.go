@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/internal/server/repository"
+	"google.golang.org/grpc"
+)
+
+type countingDumper struct {
+	calls atomic.Int32
+}
+
+func (d *countingDumper) Dump(context.Context, ...repository.Repo) error {
+	d.calls.Add(1)
+	return nil
+}
+
+func TestUnaryFileDumper(t *testing.T) {
+	t.Parallel()
+
+	methods := []string{pb.Metrics_Update_FullMethodName}
+
+	tests := []struct {
+		name        string
+		method      string
+		intervalSec int
+		handlerErr  error
+		wantDumps   int32
+	}{
+		{name: "Successful update is dumped", method: pb.Metrics_Update_FullMethodName, wantDumps: 1},
+		{
+			name:       "Failed update is not dumped",
+			method:     pb.Metrics_Update_FullMethodName,
+			handlerErr: errors.New("invalid metric"),
+		},
+		{name: "Read is not dumped", method: pb.Metrics_Get_FullMethodName},
+		{name: "Periodic dump", method: pb.Metrics_Update_FullMethodName, intervalSec: 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &countingDumper{}
+			handler := func(context.Context, any) (any, error) {
+				return nil, tt.handlerErr
+			}
+			interceptor := UnaryFileDumper(d, tt.intervalSec, methods)
+
+			_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.handlerErr, err)
+			assert.Equal(t, tt.wantDumps, d.calls.Load())
+		})
+	}
+}
+
+func TestStreamFileDumper(t *testing.T) {
+	t.Parallel()
+
+	info := &grpc.StreamServerInfo{FullMethod: pb.Metrics_Push_FullMethodName}
+	methods := []string{pb.Metrics_Push_FullMethodName}
+	ss := &serverStream{ctx: context.Background()}
+
+	t.Run("Successful push is dumped", func(t *testing.T) {
+		t.Parallel()
+
+		d := &countingDumper{}
+		err := StreamFileDumper(d, 0, methods)(nil, ss, info, func(any, grpc.ServerStream) error {
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), d.calls.Load())
+	})
+
+	t.Run("Failed push is not dumped", func(t *testing.T) {
+		t.Parallel()
+
+		d := &countingDumper{}
+		err := StreamFileDumper(d, 0, methods)(nil, ss, info, func(any, grpc.ServerStream) error {
+			return errors.New("stream broken")
+		})
+		assert.Error(t, err)
+		assert.Zero(t, d.calls.Load())
+	})
+}
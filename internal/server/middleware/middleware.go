@@ -1,6 +1,21 @@
 package middleware
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
 
 // Middleware is the type for HTTP middleware functions.
 type Middleware func(next http.Handler) http.Handler
+
+// metadataValue returns the first value of the incoming gRPC metadata key, empty if there is none.
+func metadataValue(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryRecovery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		handler  grpc.UnaryHandler
+		wantCode codes.Code
+	}{
+		{
+			name: "Handler succeeds",
+			handler: func(context.Context, any) (any, error) {
+				return "ok", nil
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Handler panics",
+			handler: func(context.Context, any) (any, error) {
+				panic("nil pointer dereference")
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			info := &grpc.UnaryServerInfo{FullMethod: pb.Metrics_Update_FullMethodName}
+			_, err := UnaryRecovery()(context.Background(), nil, info, tt.handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestStreamRecovery(t *testing.T) {
+	t.Parallel()
+
+	info := &grpc.StreamServerInfo{FullMethod: pb.Metrics_Push_FullMethodName}
+	err := StreamRecovery()(nil, nil, info, func(any, grpc.ServerStream) error {
+		panic("nil pointer dereference")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
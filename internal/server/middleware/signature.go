@@ -2,10 +2,16 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 
+	"github.com/yogenyslav/ya-metrics/internal/pb"
 	"github.com/yogenyslav/ya-metrics/pkg/secure"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 const headerSignature = "HashSHA256"
@@ -50,3 +56,44 @@ func WithSignature(key string) Middleware {
 		})
 	}
 }
+
+// UnarySignature is the gRPC counterpart of WithSignature, it checks the signature of the request message
+// passed in metadata, unsigned requests pass as in HTTP.
+func UnarySignature(key string) grpc.UnaryServerInterceptor {
+	var sg SignatureGenerator
+	if key != "" {
+		sg = secure.NewSignatureGenerator(key)
+	}
+
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		incomingSignature := metadataValue(ctx, pb.SignatureMetadata)
+		if key == "" || incomingSignature == "" {
+			return handler(ctx, req)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return nil, status.Error(codes.Internal, "failed to read request message")
+		}
+		data, err := pb.SignedPayload(msg)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to read request message")
+		}
+		if incomingSignature != sg.SignatureSHA256(data) {
+			return nil, status.Error(codes.InvalidArgument, "invalid signature")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamSignature is the gRPC counterpart of WithSignature for streams. Metadata carries a single signature
+// which cannot cover messages not sent yet, so signed streams are rejected and unsigned ones pass as in HTTP.
+func StreamSignature(key string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if key != "" && metadataValue(ss.Context(), pb.SignatureMetadata) != "" {
+			return status.Error(codes.Unimplemented, "signed streams are not supported")
+		}
+		return handler(srv, ss)
+	}
+}
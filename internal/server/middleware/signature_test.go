@@ -2,12 +2,20 @@ package middleware
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/pkg"
 	"github.com/yogenyslav/ya-metrics/pkg/secure"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestWithSignature(t *testing.T) {
@@ -80,3 +88,79 @@ func TestWithSignature(t *testing.T) {
 		assert.Equal(t, "test", recorder.Body.String())
 	})
 }
+
+func TestUnarySignature(t *testing.T) {
+	t.Parallel()
+
+	key := "secure_key"
+	req := &pb.UpdateRequest{Metric: &pb.Metric{
+		Id:     "Alloc",
+		Type:   pb.MetricType_METRIC_TYPE_GAUGE,
+		Value:  pkg.Ptr(1.5),
+		Labels: map[string]string{"a": "1", "b": "2", "c": "3"},
+	}}
+	data, err := pb.SignedPayload(req)
+	require.NoError(t, err)
+	signature := secure.NewSignatureGenerator(key).SignatureSHA256(data)
+
+	handler := func(context.Context, any) (any, error) {
+		return &pb.UpdateResponse{}, nil
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		signature string
+		wantCode  codes.Code
+	}{
+		{name: "Valid signature", key: key, signature: signature, wantCode: codes.OK},
+		{name: "Invalid signature", key: key, signature: "invalid_signature", wantCode: codes.InvalidArgument},
+		{name: "Missing signature", key: key, wantCode: codes.OK},
+		{name: "No key", signature: "invalid_signature", wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			if tt.signature != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(pb.SignatureMetadata, tt.signature))
+			}
+			info := &grpc.UnaryServerInfo{FullMethod: pb.Metrics_Update_FullMethodName}
+
+			_, err := UnarySignature(tt.key)(ctx, req, info, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestStreamSignature(t *testing.T) {
+	t.Parallel()
+
+	info := &grpc.StreamServerInfo{FullMethod: pb.Metrics_Push_FullMethodName}
+	handler := func(any, grpc.ServerStream) error {
+		return nil
+	}
+	signed := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pb.SignatureMetadata, "signature"))
+
+	tests := []struct {
+		name     string
+		key      string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{name: "Signed stream", key: "secure_key", ctx: signed, wantCode: codes.Unimplemented},
+		{name: "Unsigned stream", key: "secure_key", ctx: context.Background(), wantCode: codes.OK},
+		{name: "No key", ctx: signed, wantCode: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := StreamSignature(tt.key)(nil, &serverStream{ctx: tt.ctx}, info, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
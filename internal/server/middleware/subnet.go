@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"slices"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// trusted reports whether the reported agent address belongs to the subnet, invalid subnet trusts everyone.
+func trusted(subnet netip.Prefix, realIP string) bool {
+	if !subnet.IsValid() {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(realIP)
+	if err != nil {
+		return false
+	}
+	return subnet.Contains(addr.Unmap())
+}
+
+// WithTrustedSubnet is a middleware that rejects requests whose X-Real-IP is outside the trusted subnet.
+func WithTrustedSubnet(subnet netip.Prefix) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !trusted(subnet, r.Header.Get(model.RealIPHeader)) {
+				http.Error(w, "untrusted agent address", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// UnaryTrustedSubnet is the gRPC counterpart of WithTrustedSubnet applied to the given methods only.
+func UnaryTrustedSubnet(subnet netip.Prefix, methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if slices.Contains(methods, info.FullMethod) && !trustedContext(ctx, subnet) {
+			return nil, status.Error(codes.PermissionDenied, "untrusted agent address")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamTrustedSubnet is the gRPC counterpart of WithTrustedSubnet for streams of the given methods.
+func StreamTrustedSubnet(subnet netip.Prefix, methods ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if slices.Contains(methods, info.FullMethod) && !trustedContext(ss.Context(), subnet) {
+			return status.Error(codes.PermissionDenied, "untrusted agent address")
+		}
+		return handler(srv, ss)
+	}
+}
+
+func trustedContext(ctx context.Context, subnet netip.Prefix) bool {
+	return trusted(subnet, metadataValue(ctx, pb.RealIPMetadata))
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestWithTrustedSubnet(t *testing.T) {
+	t.Parallel()
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		subnet   netip.Prefix
+		realIP   string
+		wantCode int
+	}{
+		{
+			name:     "Address in subnet",
+			subnet:   netip.MustParsePrefix("192.168.1.0/24"),
+			realIP:   "192.168.1.10",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "IPv4-mapped address in subnet",
+			subnet:   netip.MustParsePrefix("192.168.1.0/24"),
+			realIP:   "::ffff:192.168.1.10",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Address outside subnet",
+			subnet:   netip.MustParsePrefix("192.168.1.0/24"),
+			realIP:   "10.0.0.1",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Missing address",
+			subnet:   netip.MustParsePrefix("192.168.1.0/24"),
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Invalid address",
+			subnet:   netip.MustParsePrefix("192.168.1.0/24"),
+			realIP:   "localhost",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "No trusted subnet",
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodPost, "/update/", http.NoBody)
+			if tt.realIP != "" {
+				req.Header.Set(model.RealIPHeader, tt.realIP)
+			}
+
+			recorder := httptest.NewRecorder()
+			WithTrustedSubnet(tt.subnet)(h).ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantCode, recorder.Code)
+		})
+	}
+}
+
+func TestUnaryTrustedSubnet(t *testing.T) {
+	t.Parallel()
+
+	subnet := netip.MustParsePrefix("10.0.0.0/8")
+	handler := func(context.Context, any) (any, error) {
+		return "ok", nil
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		realIP   string
+		wantCode codes.Code
+	}{
+		{
+			name:     "Update from trusted address",
+			method:   pb.Metrics_Update_FullMethodName,
+			realIP:   "10.1.2.3",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "Update from untrusted address",
+			method:   pb.Metrics_Update_FullMethodName,
+			realIP:   "192.168.1.1",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "Read from untrusted address",
+			method:   pb.Metrics_Get_FullMethodName,
+			realIP:   "192.168.1.1",
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pb.RealIPMetadata, tt.realIP))
+			interceptor := UnaryTrustedSubnet(subnet, pb.Metrics_Update_FullMethodName)
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamTrustedSubnet(t *testing.T) {
+	t.Parallel()
+
+	subnet := netip.MustParsePrefix("10.0.0.0/8")
+	interceptor := StreamTrustedSubnet(subnet, pb.Metrics_Push_FullMethodName)
+	info := &grpc.StreamServerInfo{FullMethod: pb.Metrics_Push_FullMethodName}
+	handler := func(any, grpc.ServerStream) error {
+		return nil
+	}
+
+	t.Run("Trusted address", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(pb.RealIPMetadata, "10.0.0.1"))
+		err := interceptor(nil, &serverStream{ctx: ctx}, info, handler)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Missing address", func(t *testing.T) {
+		t.Parallel()
+
+		err := interceptor(nil, &serverStream{ctx: context.Background()}, info, handler)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
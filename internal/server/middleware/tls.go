@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/yogenyslav/ya-metrics/internal/server/audit"
+)
+
+// WithClientCert is a middleware that records the subject of the verified client certificate as the agent identity.
+func WithClientCert() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+				subject := r.TLS.VerifiedChains[0][0].Subject.String()
+				r = r.WithContext(audit.WithAgent(r.Context(), subject))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yogenyslav/ya-metrics/internal/server/audit"
+)
+
+func TestWithClientCert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		state     *tls.ConnectionState
+		wantAgent string
+	}{
+		{
+			name: "Verified client certificate",
+			state: &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{
+					{Subject: pkix.Name{CommonName: "agent-1", Organization: []string{"metrics"}}},
+				}},
+			},
+			wantAgent: "CN=agent-1,O=metrics",
+		},
+		{
+			name:  "No client certificate",
+			state: &tls.ConnectionState{},
+		},
+		{
+			name: "Plain HTTP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var agent string
+			h := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				agent = audit.AgentFromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/update/", http.NoBody)
+			req.TLS = tt.state
+			WithClientCert()(h).ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.wantAgent, agent)
+		})
+	}
+}
@@ -14,6 +14,17 @@ type Repo interface {
 	GetMetrics(ctx context.Context) ([]*model.MetricsDto, error)
 }
 
+// SilenceSource is an interface for repositories that hold alert silences to be dumped along with metrics.
+type SilenceSource interface {
+	GetSilences(ctx context.Context) ([]model.Silence, error)
+}
+
+// dumpData is the content of the dump file.
+type dumpData struct {
+	Metrics  []*model.MetricsDto `json:"metrics"`
+	Silences []model.Silence     `json:"silences,omitempty"`
+}
+
 // fileDumper is a struct to dump data to file.
 type fileDumper struct {
 	filePath string
@@ -26,20 +37,24 @@ func NewDumper(filePath string) *fileDumper {
 	}
 }
 
-// Dump data to file.
-func (d *fileDumper) Dump(ctx context.Context, gaugeRepo Repo, counterRepo Repo) error {
-	gaugeMetrics, err := gaugeRepo.GetMetrics(ctx)
-	if err != nil {
-		return errs.Wrap(err, "get gauge metrics")
-	}
-	counterMetrics, err := counterRepo.GetMetrics(ctx)
-	if err != nil {
-		return errs.Wrap(err, "get counter metrics")
-	}
+// Dump data of all repos to file, silences are dumped from repos implementing SilenceSource.
+func (d *fileDumper) Dump(ctx context.Context, repos ...Repo) error {
+	v := dumpData{Metrics: []*model.MetricsDto{}}
+	for _, repo := range repos {
+		metrics, err := repo.GetMetrics(ctx)
+		if err != nil {
+			return errs.Wrap(err, "get metrics")
+		}
+		v.Metrics = append(v.Metrics, metrics...)
 
-	v := make([]*model.MetricsDto, 0, len(gaugeMetrics)+len(counterMetrics))
-	v = append(v, gaugeMetrics...)
-	v = append(v, counterMetrics...)
+		if src, ok := repo.(SilenceSource); ok {
+			silences, err := src.GetSilences(ctx)
+			if err != nil {
+				return errs.Wrap(err, "get silences")
+			}
+			v.Silences = append(v.Silences, silences...)
+		}
+	}
 
 	f, err := os.OpenFile(d.filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.ModePerm)
 	if err != nil {
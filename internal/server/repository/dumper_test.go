@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -30,27 +31,56 @@ func TestRestoreMetrics(t *testing.T) {
 
 	metricsData := []byte(`[
 		{"id":"gauge1","type":"gauge","value":12.34},
-		{"id":"counter1","type":"counter","delta":56}
+		{"id":"counter1","type":"counter","delta":56},
+		{"id":"latency","type":"histogram","histogram":{"bounds":[0.5],"counts":[1,2],"count":3,"sum":4.5}}
 	]`)
 
 	filePath := t.TempDir() + "/metrics.json"
 	err := os.WriteFile(filePath, metricsData, os.ModePerm)
 	require.NoError(t, err)
 
-	gaugeMetrics, counterMetrics, err := RestoreMetrics(filePath)
+	state, err := RestoreMetrics(filePath)
 	require.NoError(t, err)
 
-	gaugeMetric, ok := gaugeMetrics["gauge1"]
+	gaugeMetric, ok := state.Gauges["gauge1"]
 	require.True(t, ok)
 	assert.Equal(t, "gauge1", gaugeMetric.ID)
 	assert.Equal(t, "gauge", gaugeMetric.Type)
 	assert.Equal(t, 12.34, gaugeMetric.Value)
 
-	counterMetric, ok := counterMetrics["counter1"]
+	counterMetric, ok := state.Counters["counter1"]
 	require.True(t, ok)
 	assert.Equal(t, "counter1", counterMetric.ID)
 	assert.Equal(t, "counter", counterMetric.Type)
 	assert.Equal(t, int64(56), counterMetric.Value)
+
+	histogram, ok := state.Histograms["latency"]
+	require.True(t, ok)
+	assert.Equal(t, model.HistogramData{Bounds: []float64{0.5}, Counts: []int64{1, 2}, Count: 3, Sum: 4.5},
+		histogram.HistogramData)
+}
+
+func TestRestoreMetrics_silences(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"metrics": [{"id":"gauge1","type":"gauge","value":12.34}],
+		"silences": [
+			{"id":"s1","metric":"load","starts_at":"2026-04-01T12:00:00Z","ends_at":"2026-04-01T13:00:00Z"}
+		]
+	}`)
+
+	filePath := t.TempDir() + "/metrics.json"
+	require.NoError(t, os.WriteFile(filePath, data, os.ModePerm))
+
+	state, err := RestoreMetrics(filePath)
+	require.NoError(t, err)
+
+	assert.Contains(t, state.Gauges, "gauge1")
+	ts := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []model.Silence{
+		{ID: "s1", MetricID: "load", StartsAt: ts, EndsAt: ts.Add(time.Hour)},
+	}, state.Silences)
 }
 
 func Test_fileDumper_Dump(t *testing.T) {
@@ -61,6 +91,7 @@ func Test_fileDumper_Dump(t *testing.T) {
 
 	gaugeRepo := new(mocks.MockGaugeRepo)
 	counterRepo := new(mocks.MockCounterRepo)
+	histogramRepo := new(mocks.MockHistogramRepo)
 
 	gaugeMetrics := []*model.MetricsDto{
 		{ID: "gauge1", Type: model.Gauge, Value: pkg.Ptr(12.34)},
@@ -71,17 +102,38 @@ func Test_fileDumper_Dump(t *testing.T) {
 
 	gaugeRepo.On("GetMetrics", mock.Anything).Return(gaugeMetrics, nil)
 	counterRepo.On("GetMetrics", mock.Anything).Return(counterMetrics, nil)
-
-	err := dumper.Dump(context.Background(), gaugeRepo, counterRepo)
+	histogramRepo.On("GetMetrics", mock.Anything).Return([]*model.MetricsDto{
+		{
+			ID:        "latency",
+			Type:      model.Histogram,
+			Histogram: &model.HistogramData{Bounds: []float64{0.5}, Counts: []int64{1, 2}, Count: 3, Sum: 4.5},
+		},
+	}, nil)
+
+	ts := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	silenceRepo := NewSilenceInMemRepo([]model.Silence{
+		{ID: "s1", MetricID: "load", StartsAt: ts, EndsAt: ts.Add(time.Hour), CreatedAt: ts},
+	})
+
+	err := dumper.Dump(context.Background(), gaugeRepo, counterRepo, histogramRepo, silenceRepo)
 	require.NoError(t, err)
 
 	storage, err := os.ReadFile(filePath)
 	require.NoError(t, err)
 
-	want := `[
-		{"id":"gauge1","type":"gauge","value":12.34},
-		{"id":"counter1","type":"counter","delta":56}
-	]`
+	want := `{
+		"metrics": [
+			{"id":"gauge1","type":"gauge","value":12.34},
+			{"id":"counter1","type":"counter","delta":56},
+			{"id":"latency","type":"histogram","histogram":{"bounds":[0.5],"counts":[1,2],"count":3,"sum":4.5}}
+		],
+		"silences": [
+			{
+				"id":"s1","metric":"load","labels":null,"comment":"",
+				"starts_at":"2026-04-01T12:00:00Z","ends_at":"2026-04-01T13:00:00Z","created_at":"2026-04-01T12:00:00Z"
+			}
+		]
+	}`
 
 	assert.JSONEq(t, want, string(storage))
 }
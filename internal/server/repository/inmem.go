@@ -1,61 +1,91 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"os"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 )
 
-// StorageState represents the in-memory storage state for metrics.
+// StorageState represents the in-memory storage state for metrics keyed by model.SeriesKey.
 type StorageState[T int64 | float64] map[string]*model.Metrics[T]
 
-// RestoreMetrics restores metrics from the file.
-func RestoreMetrics(
-	filePath string,
-) (gaugeMetrics StorageState[float64], countMetrics StorageState[int64], err error) {
+// RestoredState holds metrics and silences restored from the dump file.
+type RestoredState struct {
+	Gauges     StorageState[float64]
+	Counters   StorageState[int64]
+	Histograms HistogramState
+	Summaries  SummaryState
+	Silences   []model.Silence
+}
+
+// RestoreMetrics restores metrics and silences from the file.
+//
+// Files holding a plain array of metrics written by older versions are restored as well.
+func RestoreMetrics(filePath string) (*RestoredState, error) {
 	f, err := os.OpenFile(filePath, os.O_RDONLY|os.O_CREATE, os.ModePerm)
 	if err != nil {
-		return nil, nil, errs.Wrap(err, "open file for restore")
+		return nil, errs.Wrap(err, "open file for restore")
 	}
 	defer f.Close()
 
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, nil, errs.Wrap(err, "read data from file")
+		return nil, errs.Wrap(err, "read data from file")
 	}
 
+	state := &RestoredState{}
 	if len(data) == 0 {
-		return nil, nil, nil
+		return state, nil
 	}
 
-	var v []*model.MetricsDto
-	err = json.Unmarshal(data, &v)
+	var v dumpData
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &v.Metrics)
+	} else {
+		err = json.Unmarshal(data, &v)
+	}
 	if err != nil {
-		return nil, nil, errs.Wrap(err, "unmarshal data")
+		return nil, errs.Wrap(err, "unmarshal data")
 	}
+	state.Silences = v.Silences
 
-	gaugeMetrics = make(StorageState[float64])
-	countMetrics = make(StorageState[int64])
-	for _, m := range v {
+	state.Gauges = make(StorageState[float64])
+	state.Counters = make(StorageState[int64])
+	state.Histograms = make(HistogramState)
+	state.Summaries = make(SummaryState)
+	for _, m := range v.Metrics {
+		key := model.SeriesKey(m.ID, m.Labels)
 		switch m.Type {
 		case model.Gauge:
-			gaugeMetrics[m.ID] = m.ToGaugeMetric()
+			state.Gauges[key] = m.ToGaugeMetric()
 		case model.Counter:
-			countMetrics[m.ID] = m.ToCounterMetric()
+			state.Counters[key] = m.ToCounterMetric()
+		case model.Histogram:
+			if m.Histogram != nil {
+				state.Histograms[key] = m.ToHistogramMetric()
+			}
+		case model.Summary:
+			if m.Summary != nil && m.Summary.Sketch != nil {
+				state.Summaries[key] = m.ToSummaryMetric()
+			}
 		}
 	}
 
-	return gaugeMetrics, countMetrics, nil
+	return state, nil
 }
 
 // MetricInMemRepo is an in-memory repository for metrics.
 type MetricInMemRepo[T int64 | float64] struct {
 	storage StorageState[T]
+	samples map[string][]model.Sample[T]
 	mu      *sync.RWMutex
 }
 
@@ -85,39 +115,65 @@ func (r *MetricInMemRepo[T]) GetMetrics(_ context.Context) ([]*model.MetricsDto,
 	return metrics, nil
 }
 
-// Get returns the value of a metric by its name and a bool flag to check if it exists.
-func (r *MetricInMemRepo[T]) Get(_ context.Context, metricName, _ string) (*model.Metrics[T], error) {
+// Get returns a copy of the metric series identified by its name and labels.
+func (r *MetricInMemRepo[T]) Get(
+	_ context.Context,
+	metricName, _ string,
+	labels model.Labels,
+) (*model.Metrics[T], error) {
 	r.mu.RLock()
-	value, exists := r.storage[metricName]
+	value, exists := r.storage[model.SeriesKey(metricName, labels)]
 	r.mu.RUnlock()
 	if !exists {
 		return nil, errors.New("value not found")
 	}
-	return value, nil
+
+	m := *value
+	return &m, nil
 }
 
-// Set sets the value of a metric by its name.
+// Set sets the value of a metric series and marks it updated now.
 func (r *MetricInMemRepo[T]) Set(_ context.Context, m *model.Metrics[T]) error {
+	key := model.SeriesKey(m.ID, m.Labels)
+
 	r.mu.Lock()
-	if metric, ok := r.storage[m.ID]; ok {
+	if metric, ok := r.storage[key]; ok {
 		metric.Value = m.Value
+		metric.UpdatedAt = time.Now()
 	} else {
-		r.storage[m.ID] = m
+		r.storage[key] = copyMetric(m)
 	}
 	r.mu.Unlock()
 	return nil
 }
 
-// Update updates the value of a metric by adding the delta to the current value.
-func (r *MetricInMemRepo[T]) Update(_ context.Context, m *model.Metrics[T]) error {
+// Update adds the delta to the value of a metric series, marks it updated now and returns the new value.
+func (r *MetricInMemRepo[T]) Update(_ context.Context, m *model.Metrics[T]) (T, error) {
+	key := model.SeriesKey(m.ID, m.Labels)
+
 	r.mu.Lock()
-	if metric, exists := r.storage[m.ID]; exists {
+	defer r.mu.Unlock()
+
+	metric, exists := r.storage[key]
+	if exists {
 		metric.Value += m.Value
+		metric.UpdatedAt = time.Now()
 	} else {
-		r.storage[m.ID] = m
+		metric = copyMetric(m)
+		r.storage[key] = metric
+	}
+	return metric.Value, nil
+}
+
+// copyMetric detaches the stored metric from the caller, which may return m to a pool.
+func copyMetric[T int64 | float64](m *model.Metrics[T]) *model.Metrics[T] {
+	return &model.Metrics[T]{
+		ID:        m.ID,
+		Type:      m.Type,
+		Value:     m.Value,
+		Labels:    m.Labels.Clone(),
+		UpdatedAt: time.Now(),
 	}
-	r.mu.Unlock()
-	return nil
 }
 
 // List returns a list of all metrics in the repository.
@@ -130,3 +186,100 @@ func (r *MetricInMemRepo[T]) List(_ context.Context) ([]model.Metrics[T], error)
 	r.mu.RUnlock()
 	return metrics, nil
 }
+
+// Append adds a sample to the metric history keeping samples ordered by timestamp.
+func (r *MetricInMemRepo[T]) Append(_ context.Context, s *model.Sample[T]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.samples == nil {
+		r.samples = make(map[string][]model.Sample[T])
+	}
+
+	key := model.SeriesKey(s.ID, s.Labels)
+	series := r.samples[key]
+	idx := sort.Search(len(series), func(i int) bool {
+		return !series[i].Timestamp.Before(s.Timestamp)
+	})
+
+	if idx < len(series) && series[idx].Timestamp.Equal(s.Timestamp) {
+		series[idx].Value = s.Value
+		return nil
+	}
+
+	series = append(series, model.Sample[T]{})
+	copy(series[idx+1:], series[idx:])
+	series[idx] = *s
+	series[idx].Labels = s.Labels.Clone()
+	r.samples[key] = series
+
+	return nil
+}
+
+// Range returns metric samples with timestamps in [from, to] ordered by timestamp.
+func (r *MetricInMemRepo[T]) Range(
+	_ context.Context,
+	metricName, _ string,
+	labels model.Labels,
+	from, to time.Time,
+) ([]model.Sample[T], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	series := r.samples[model.SeriesKey(metricName, labels)]
+	start := sort.Search(len(series), func(i int) bool {
+		return !series[i].Timestamp.Before(from)
+	})
+	end := sort.Search(len(series), func(i int) bool {
+		return series[i].Timestamp.After(to)
+	})
+
+	if start >= end {
+		return []model.Sample[T]{}, nil
+	}
+
+	result := make([]model.Sample[T], end-start)
+	copy(result, series[start:end])
+	return result, nil
+}
+
+// LatestBefore returns the latest metric sample recorded before the given time, nil if there is none.
+func (r *MetricInMemRepo[T]) LatestBefore(
+	_ context.Context,
+	metricName, _ string,
+	labels model.Labels,
+	before time.Time,
+) (*model.Sample[T], error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	series := r.samples[model.SeriesKey(metricName, labels)]
+	idx := sort.Search(len(series), func(i int) bool {
+		return !series[i].Timestamp.Before(before)
+	})
+	if idx == 0 {
+		return nil, nil
+	}
+
+	sample := series[idx-1]
+	return &sample, nil
+}
+
+// DeleteSamplesBefore removes samples of all series recorded before the given time.
+func (r *MetricInMemRepo[T]) DeleteSamplesBefore(_ context.Context, _ string, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, series := range r.samples {
+		idx := sort.Search(len(series), func(i int) bool {
+			return !series[i].Timestamp.Before(before)
+		})
+		if idx == len(series) {
+			delete(r.samples, key)
+			continue
+		}
+		r.samples[key] = series[idx:]
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// HistogramState represents the in-memory storage state for histograms keyed by model.SeriesKey.
+type HistogramState map[string]*model.HistogramMetric
+
+// HistogramInMemRepo is an in-memory repository for histogram metrics.
+type HistogramInMemRepo struct {
+	storage HistogramState
+	mu      *sync.RWMutex
+}
+
+// NewHistogramInMemRepo creates a new instance of HistogramInMemRepo.
+func NewHistogramInMemRepo(state HistogramState) *HistogramInMemRepo {
+	if state == nil {
+		state = make(HistogramState)
+	}
+
+	return &HistogramInMemRepo{
+		storage: state,
+		mu:      &sync.RWMutex{},
+	}
+}
+
+// GetMetrics returns all histograms in MetricsDto format.
+func (r *HistogramInMemRepo) GetMetrics(_ context.Context) ([]*model.MetricsDto, error) {
+	r.mu.RLock()
+	metrics := make([]*model.MetricsDto, 0, len(r.storage))
+	for _, h := range r.storage {
+		metrics = append(metrics, h.ToDto())
+	}
+	r.mu.RUnlock()
+	return metrics, nil
+}
+
+// Get returns a copy of the histogram series identified by its name and labels.
+func (r *HistogramInMemRepo) Get(
+	_ context.Context,
+	metricName string,
+	labels model.Labels,
+) (*model.HistogramMetric, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h, exists := r.storage[model.SeriesKey(metricName, labels)]
+	if !exists {
+		return nil, errors.New("value not found")
+	}
+	return copyHistogram(h), nil
+}
+
+// List returns a list of all histograms in the repository.
+func (r *HistogramInMemRepo) List(_ context.Context) ([]model.HistogramMetric, error) {
+	r.mu.RLock()
+	metrics := make([]model.HistogramMetric, 0, len(r.storage))
+	for _, h := range r.storage {
+		metrics = append(metrics, *copyHistogram(h))
+	}
+	r.mu.RUnlock()
+	return metrics, nil
+}
+
+// Merge adds observations to the histogram series and marks it updated now,
+// buckets of an existing series must match.
+func (r *HistogramInMemRepo) Merge(_ context.Context, h *model.HistogramMetric) error {
+	key := model.SeriesKey(h.ID, h.Labels)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.storage[key]
+	if !exists {
+		stored = copyHistogram(h)
+		stored.UpdatedAt = time.Now()
+		r.storage[key] = stored
+		return nil
+	}
+
+	if !stored.SameBuckets(&h.HistogramData) {
+		return errs.Wrap(errs.ErrBucketsMismatch, h.ID)
+	}
+	stored.Merge(&h.HistogramData)
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func copyHistogram(h *model.HistogramMetric) *model.HistogramMetric {
+	return &model.HistogramMetric{
+		ID:            h.ID,
+		Labels:        h.Labels.Clone(),
+		UpdatedAt:     h.UpdatedAt,
+		HistogramData: *h.HistogramData.Clone(),
+	}
+}
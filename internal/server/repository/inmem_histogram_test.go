@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+func TestHistogramInMemRepo_Merge(t *testing.T) {
+	t.Parallel()
+
+	histogram := func(bounds []float64, counts []int64, sum float64) *model.HistogramMetric {
+		var count int64
+		for _, c := range counts {
+			count += c
+		}
+		return &model.HistogramMetric{
+			ID:     "latency",
+			Labels: model.Labels{"path": "/update/"},
+			HistogramData: model.HistogramData{
+				Bounds: bounds,
+				Counts: counts,
+				Count:  count,
+				Sum:    sum,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		merges  []*model.HistogramMetric
+		want    *model.HistogramMetric
+		wantErr error
+	}{
+		{
+			name:   "Merge into new series",
+			merges: []*model.HistogramMetric{histogram([]float64{0.1, 1}, []int64{1, 2, 3}, 10)},
+			want:   histogram([]float64{0.1, 1}, []int64{1, 2, 3}, 10),
+		},
+		{
+			name: "Merge adds observations",
+			merges: []*model.HistogramMetric{
+				histogram([]float64{0.1, 1}, []int64{1, 2, 3}, 10),
+				histogram([]float64{0.1, 1}, []int64{4, 0, 1}, 2.5),
+			},
+			want: histogram([]float64{0.1, 1}, []int64{5, 2, 4}, 12.5),
+		},
+		{
+			name: "Merge with different buckets",
+			merges: []*model.HistogramMetric{
+				histogram([]float64{0.1, 1}, []int64{1, 2, 3}, 10),
+				histogram([]float64{0.5}, []int64{1, 1}, 1),
+			},
+			want:    histogram([]float64{0.1, 1}, []int64{1, 2, 3}, 10),
+			wantErr: errs.ErrBucketsMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewHistogramInMemRepo(nil)
+			before := time.Now()
+
+			var err error
+			for _, h := range tt.merges {
+				err = repo.Merge(context.Background(), h)
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			got, err := repo.Get(context.Background(), "latency", model.Labels{"path": "/update/"})
+			require.NoError(t, err)
+			assert.False(t, got.UpdatedAt.Before(before))
+			got.UpdatedAt = time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHistogramInMemRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	repo := NewHistogramInMemRepo(HistogramState{
+		"latency": {ID: "latency", HistogramData: model.HistogramData{Bounds: []float64{1}, Counts: []int64{1, 0}, Count: 1}},
+	})
+
+	got, err := repo.Get(context.Background(), "latency", nil)
+	require.NoError(t, err)
+	got.Counts[0] = 100
+
+	stored, err := repo.Get(context.Background(), "latency", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stored.Counts[0])
+
+	_, err = repo.Get(context.Background(), "unknown", nil)
+	assert.Error(t, err)
+}
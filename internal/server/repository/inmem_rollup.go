@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+type rollupKey struct {
+	resolution int64
+	series     string
+}
+
+// RollupInMemRepo is an in-memory repository for downsampled metric history.
+type RollupInMemRepo struct {
+	storage map[rollupKey][]model.Rollup
+	mu      *sync.RWMutex
+}
+
+// NewRollupInMemRepo creates a new instance of RollupInMemRepo.
+func NewRollupInMemRepo() *RollupInMemRepo {
+	return &RollupInMemRepo{
+		storage: make(map[rollupKey][]model.Rollup),
+		mu:      &sync.RWMutex{},
+	}
+}
+
+// Save stores rollups replacing the ones computed earlier for the same interval.
+func (r *RollupInMemRepo) Save(_ context.Context, rollups ...model.Rollup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rollup := range rollups {
+		key := rollupKey{
+			resolution: rollup.Resolution,
+			series:     model.SeriesKey(rollup.ID+"/"+rollup.Type, rollup.Labels),
+		}
+
+		series := r.storage[key]
+		idx := sort.Search(len(series), func(i int) bool {
+			return !series[i].Timestamp.Before(rollup.Timestamp)
+		})
+
+		rollup.Labels = rollup.Labels.Clone()
+		if idx < len(series) && series[idx].Timestamp.Equal(rollup.Timestamp) {
+			series[idx] = rollup
+			continue
+		}
+
+		series = append(series, model.Rollup{})
+		copy(series[idx+1:], series[idx:])
+		series[idx] = rollup
+		r.storage[key] = series
+	}
+
+	return nil
+}
+
+// Range returns rollups of the given resolution in seconds with timestamps in [from, to] ordered by timestamp.
+func (r *RollupInMemRepo) Range(
+	_ context.Context,
+	metricID, metricType string,
+	labels model.Labels,
+	resolution int64,
+	from, to time.Time,
+) ([]model.Rollup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	series := r.storage[rollupKey{
+		resolution: resolution,
+		series:     model.SeriesKey(metricID+"/"+metricType, labels),
+	}]
+	start := sort.Search(len(series), func(i int) bool {
+		return !series[i].Timestamp.Before(from)
+	})
+	end := sort.Search(len(series), func(i int) bool {
+		return series[i].Timestamp.After(to)
+	})
+
+	if start >= end {
+		return []model.Rollup{}, nil
+	}
+
+	result := make([]model.Rollup, end-start)
+	copy(result, series[start:end])
+	return result, nil
+}
+
+// DeleteBefore removes rollups of the given resolution in seconds recorded before the given time.
+func (r *RollupInMemRepo) DeleteBefore(_ context.Context, resolution int64, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, series := range r.storage {
+		if key.resolution != resolution {
+			continue
+		}
+
+		idx := sort.Search(len(series), func(i int) bool {
+			return !series[i].Timestamp.Before(before)
+		})
+		if idx == len(series) {
+			delete(r.storage, key)
+			continue
+		}
+		r.storage[key] = series[idx:]
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+func TestRollupInMemRepo(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	rollup := func(offsetMin int, resolution int64, last float64) model.Rollup {
+		return model.Rollup{
+			ID:         "load",
+			Type:       model.Gauge,
+			Labels:     model.Labels{"host": "a"},
+			Resolution: resolution,
+			Timestamp:  base.Add(time.Duration(offsetMin) * time.Minute),
+			Count:      1,
+			Last:       last,
+		}
+	}
+
+	repo := NewRollupInMemRepo()
+	require.NoError(t, repo.Save(
+		context.Background(),
+		rollup(2, 60, 3),
+		rollup(0, 60, 1),
+		rollup(1, 60, 2),
+		rollup(0, 3600, 10),
+	))
+	require.NoError(t, repo.Save(context.Background(), rollup(1, 60, 5)))
+
+	labels := model.Labels{"host": "a"}
+	got, err := repo.Range(context.Background(), "load", model.Gauge, labels, 60, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []model.Rollup{rollup(0, 60, 1), rollup(1, 60, 5), rollup(2, 60, 3)}, got)
+
+	got, err = repo.Range(context.Background(), "load", model.Counter, labels, 60, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	require.NoError(t, repo.DeleteBefore(context.Background(), 60, base.Add(2*time.Minute)))
+
+	got, err = repo.Range(context.Background(), "load", model.Gauge, labels, 60, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []model.Rollup{rollup(2, 60, 3)}, got)
+
+	got, err = repo.Range(context.Background(), "load", model.Gauge, labels, 3600, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []model.Rollup{rollup(0, 3600, 10)}, got)
+}
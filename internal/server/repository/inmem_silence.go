@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// SilenceInMemRepo is an in-memory repository for alert silences.
+type SilenceInMemRepo struct {
+	storage map[string]model.Silence
+	mu      *sync.RWMutex
+}
+
+// NewSilenceInMemRepo creates a new instance of SilenceInMemRepo.
+func NewSilenceInMemRepo(silences []model.Silence) *SilenceInMemRepo {
+	storage := make(map[string]model.Silence, len(silences))
+	for _, s := range silences {
+		storage[s.ID] = s
+	}
+
+	return &SilenceInMemRepo{
+		storage: storage,
+		mu:      &sync.RWMutex{},
+	}
+}
+
+// Create stores a new silence.
+func (r *SilenceInMemRepo) Create(_ context.Context, silence model.Silence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	silence.Labels = silence.Labels.Clone()
+	r.storage[silence.ID] = silence
+	return nil
+}
+
+// List returns all silences ordered by creation time.
+func (r *SilenceInMemRepo) List(_ context.Context) ([]model.Silence, error) {
+	r.mu.RLock()
+	silences := make([]model.Silence, 0, len(r.storage))
+	for _, s := range r.storage {
+		silences = append(silences, s)
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(silences, func(a, b model.Silence) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return silences, nil
+}
+
+// Expire ends the silence at the given time unless it has already ended.
+func (r *SilenceInMemRepo) Expire(_ context.Context, id string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	silence, ok := r.storage[id]
+	if !ok {
+		return errs.Wrap(errs.ErrSilenceNotFound, id)
+	}
+	if now.Before(silence.EndsAt) {
+		silence.EndsAt = now
+		r.storage[id] = silence
+	}
+	return nil
+}
+
+// GetMetrics implements Repo, so silences are dumped together with metrics, it holds no metrics.
+func (r *SilenceInMemRepo) GetMetrics(_ context.Context) ([]*model.MetricsDto, error) {
+	return []*model.MetricsDto{}, nil
+}
+
+// GetSilences returns all silences to be dumped.
+func (r *SilenceInMemRepo) GetSilences(ctx context.Context) ([]model.Silence, error) {
+	return r.List(ctx)
+}
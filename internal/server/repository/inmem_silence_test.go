@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+func TestSilenceInMemRepo(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	restored := model.Silence{ID: "s1", MetricID: "load", StartsAt: ts, EndsAt: ts.Add(time.Hour), CreatedAt: ts}
+	created := model.Silence{
+		ID:        "s2",
+		Labels:    model.Labels{"dc": "east"},
+		StartsAt:  ts,
+		EndsAt:    ts.Add(time.Hour),
+		CreatedAt: ts.Add(time.Minute),
+	}
+
+	repo := NewSilenceInMemRepo([]model.Silence{restored})
+	require.NoError(t, repo.Create(context.Background(), created))
+
+	silences, err := repo.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []model.Silence{restored, created}, silences)
+
+	require.NoError(t, repo.Expire(context.Background(), "s2", ts.Add(30*time.Minute)))
+	// expiring an ended silence keeps its end
+	require.NoError(t, repo.Expire(context.Background(), "s2", ts.Add(2*time.Hour)))
+	require.ErrorIs(t, repo.Expire(context.Background(), "s3", ts), errs.ErrSilenceNotFound)
+
+	silences, err = repo.GetSilences(context.Background())
+	require.NoError(t, err)
+	require.Len(t, silences, 2)
+	assert.Equal(t, ts.Add(30*time.Minute), silences[1].EndsAt)
+
+	metrics, err := repo.GetMetrics(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+}
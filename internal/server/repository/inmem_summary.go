@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// SummaryState represents the in-memory storage state for summaries keyed by model.SeriesKey.
+type SummaryState map[string]*model.SummaryMetric
+
+// SummaryInMemRepo is an in-memory repository for summary metrics.
+type SummaryInMemRepo struct {
+	storage SummaryState
+	mu      *sync.RWMutex
+}
+
+// NewSummaryInMemRepo creates a new instance of SummaryInMemRepo.
+func NewSummaryInMemRepo(state SummaryState) *SummaryInMemRepo {
+	if state == nil {
+		state = make(SummaryState)
+	}
+
+	return &SummaryInMemRepo{
+		storage: state,
+		mu:      &sync.RWMutex{},
+	}
+}
+
+// GetMetrics returns all summaries in MetricsDto format including their sketches.
+func (r *SummaryInMemRepo) GetMetrics(_ context.Context) ([]*model.MetricsDto, error) {
+	r.mu.RLock()
+	metrics := make([]*model.MetricsDto, 0, len(r.storage))
+	for _, s := range r.storage {
+		dto := s.ToDto()
+		dto.Summary.Sketch = s.Sketch.Clone()
+		metrics = append(metrics, dto)
+	}
+	r.mu.RUnlock()
+	return metrics, nil
+}
+
+// Get returns a copy of the summary series identified by its name and labels.
+func (r *SummaryInMemRepo) Get(
+	_ context.Context,
+	metricName string,
+	labels model.Labels,
+) (*model.SummaryMetric, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, exists := r.storage[model.SeriesKey(metricName, labels)]
+	if !exists {
+		return nil, errors.New("value not found")
+	}
+	return copySummary(s), nil
+}
+
+// List returns a list of all summaries in the repository.
+func (r *SummaryInMemRepo) List(_ context.Context) ([]model.SummaryMetric, error) {
+	r.mu.RLock()
+	metrics := make([]model.SummaryMetric, 0, len(r.storage))
+	for _, s := range r.storage {
+		metrics = append(metrics, *copySummary(s))
+	}
+	r.mu.RUnlock()
+	return metrics, nil
+}
+
+// Merge adds observations recorded by the sketch of s to the summary series and marks it updated now.
+func (r *SummaryInMemRepo) Merge(_ context.Context, s *model.SummaryMetric) error {
+	key := model.SeriesKey(s.ID, s.Labels)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, exists := r.storage[key]
+	if !exists {
+		stored = copySummary(s)
+		stored.UpdatedAt = time.Now()
+		r.storage[key] = stored
+		return nil
+	}
+
+	if err := stored.Sketch.Merge(s.Sketch); err != nil {
+		return errs.Wrap(err, s.ID)
+	}
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func copySummary(s *model.SummaryMetric) *model.SummaryMetric {
+	return &model.SummaryMetric{
+		ID:        s.ID,
+		Labels:    s.Labels.Clone(),
+		Sketch:    s.Sketch.Clone(),
+		UpdatedAt: s.UpdatedAt,
+	}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/sketch"
+)
+
+func TestSummaryInMemRepo_Merge(t *testing.T) {
+	t.Parallel()
+
+	labels := model.Labels{"path": "/update/"}
+	coarse := sketch.New(0.05)
+	coarse.Add(2)
+
+	tests := []struct {
+		name      string
+		merges    []*model.SummaryMetric
+		wantCount int64
+		wantSum   float64
+		wantErr   error
+	}{
+		{
+			name:      "Merge into new series",
+			merges:    []*model.SummaryMetric{model.NewSummaryMetric("latency", labels, 1, 2, 3)},
+			wantCount: 3,
+			wantSum:   6,
+		},
+		{
+			name: "Merge adds observations",
+			merges: []*model.SummaryMetric{
+				model.NewSummaryMetric("latency", labels, 1, 2, 3),
+				model.NewSummaryMetric("latency", labels, 4),
+			},
+			wantCount: 4,
+			wantSum:   10,
+		},
+		{
+			name: "Merge with different accuracy",
+			merges: []*model.SummaryMetric{
+				model.NewSummaryMetric("latency", labels, 1),
+				{ID: "latency", Labels: labels, Sketch: coarse},
+			},
+			wantCount: 1,
+			wantSum:   1,
+			wantErr:   sketch.ErrAccuracyMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := NewSummaryInMemRepo(nil)
+			before := time.Now()
+
+			var err error
+			for _, s := range tt.merges {
+				err = repo.Merge(context.Background(), s)
+			}
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			got, err := repo.Get(context.Background(), "latency", labels)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCount, got.Sketch.Count)
+			assert.InDelta(t, tt.wantSum, got.Sketch.Sum, 1e-9)
+			assert.False(t, got.UpdatedAt.Before(before))
+		})
+	}
+}
+
+func TestSummaryInMemRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	repo := NewSummaryInMemRepo(SummaryState{
+		"latency": model.NewSummaryMetric("latency", nil, 1),
+	})
+
+	got, err := repo.Get(context.Background(), "latency", nil)
+	require.NoError(t, err)
+	got.Sketch.Add(100)
+
+	stored, err := repo.Get(context.Background(), "latency", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stored.Sketch.Count)
+
+	_, err = repo.Get(context.Background(), "unknown", nil)
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,7 +59,7 @@ func TestMetricInMemRepo_Get(t *testing.T) {
 				t.Run(
 					tt.name, func(t *testing.T) {
 						t.Parallel()
-						metric, err := tt.r.Get(context.Background(), tt.args.name, model.Counter)
+						metric, err := tt.r.Get(context.Background(), tt.args.name, model.Counter, nil)
 						if tt.wantErr {
 							require.Error(t, err)
 						} else {
@@ -104,7 +105,7 @@ func TestMetricInMemRepo_Get(t *testing.T) {
 				t.Run(
 					tt.name, func(t *testing.T) {
 						t.Parallel()
-						metric, err := tt.r.Get(context.Background(), tt.args.name, model.Gauge)
+						metric, err := tt.r.Get(context.Background(), tt.args.name, model.Gauge, nil)
 						if tt.wantErr {
 							require.Error(t, err)
 						} else {
@@ -155,7 +156,7 @@ func TestMetricInMemRepo_Set(t *testing.T) {
 							Type:  model.Counter,
 							Value: tt.args.value,
 						})
-						got, err := tt.r.Get(context.Background(), tt.args.name, model.Counter)
+						got, err := tt.r.Get(context.Background(), tt.args.name, model.Counter, nil)
 						require.NoError(t, err)
 						assert.Equal(t, tt.args.value, got.Value)
 					},
@@ -188,7 +189,7 @@ func TestMetricInMemRepo_Set(t *testing.T) {
 							Type:  model.Gauge,
 							Value: tt.args.value,
 						})
-						got, err := tt.r.Get(context.Background(), tt.args.name, model.Gauge)
+						got, err := tt.r.Get(context.Background(), tt.args.name, model.Gauge, nil)
 						assert.NoError(t, err)
 						assert.Equal(t, tt.args.value, got.Value)
 					},
@@ -240,18 +241,20 @@ func TestMetricInMemRepo_Update(t *testing.T) {
 				t.Run(
 					tt.name, func(t *testing.T) {
 						t.Parallel()
-						tt.r.Update(context.Background(), &model.Metrics[int64]{
+						total, err := tt.r.Update(context.Background(), &model.Metrics[int64]{
 							ID:    tt.args.name,
 							Type:  model.Counter,
 							Value: tt.args.delta,
 						})
-						got, err := tt.r.Get(context.Background(), tt.args.name, model.Counter)
+						assert.NoError(t, err)
+						got, err := tt.r.Get(context.Background(), tt.args.name, model.Counter, nil)
 						assert.NoError(t, err)
 						expectedValue := tt.args.delta
 						if original, ok := tt.r.storage[tt.args.name]; ok {
 							expectedValue += original.Value - tt.args.delta
 						}
 						assert.Equal(t, expectedValue, got.Value)
+						assert.Equal(t, got.Value, total)
 					},
 				)
 			}
@@ -292,7 +295,7 @@ func TestMetricInMemRepo_Update(t *testing.T) {
 							Type:  model.Gauge,
 							Value: tt.args.delta,
 						})
-						got, err := tt.r.Get(context.Background(), tt.args.name, model.Gauge)
+						got, err := tt.r.Get(context.Background(), tt.args.name, model.Gauge, nil)
 						assert.NoError(t, err)
 						expectedValue := tt.args.delta
 						if original, ok := tt.r.storage[tt.args.name]; ok {
@@ -531,3 +534,238 @@ func TestMetricInMemRepo_GetMetrics(t *testing.T) {
 		assert.ElementsMatch(t, want, got)
 	})
 }
+
+func TestMetricInMemRepo_AppendRange(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	sample := func(offsetSec int, value float64) *model.Sample[float64] {
+		return &model.Sample[float64]{
+			ID:        "metric1",
+			Type:      model.Gauge,
+			Value:     value,
+			Timestamp: base.Add(time.Duration(offsetSec) * time.Second),
+		}
+	}
+
+	repo := NewMetricInMemRepo[float64](nil)
+	for _, s := range []*model.Sample[float64]{
+		sample(0, 1),
+		sample(20, 3),
+		sample(10, 2),
+		sample(30, 4),
+		sample(20, 5),
+	} {
+		require.NoError(t, repo.Append(context.Background(), s))
+	}
+
+	tests := []struct {
+		name     string
+		metricID string
+		from     time.Time
+		to       time.Time
+		want     []model.Sample[float64]
+	}{
+		{
+			name:     "Full range is ordered and deduplicated by timestamp",
+			metricID: "metric1",
+			from:     base,
+			to:       base.Add(time.Minute),
+			want:     []model.Sample[float64]{*sample(0, 1), *sample(10, 2), *sample(20, 5), *sample(30, 4)},
+		},
+		{
+			name:     "Bounds are inclusive",
+			metricID: "metric1",
+			from:     base.Add(10 * time.Second),
+			to:       base.Add(20 * time.Second),
+			want:     []model.Sample[float64]{*sample(10, 2), *sample(20, 5)},
+		},
+		{
+			name:     "Empty range",
+			metricID: "metric1",
+			from:     base.Add(11 * time.Second),
+			to:       base.Add(19 * time.Second),
+			want:     []model.Sample[float64]{},
+		},
+		{
+			name:     "Unknown metric",
+			metricID: "metric2",
+			from:     base,
+			to:       base.Add(time.Minute),
+			want:     []model.Sample[float64]{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.Range(context.Background(), tt.metricID, model.Gauge, nil, tt.from, tt.to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMetricInMemRepo_LatestBefore(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	sample := func(offsetSec int) *model.Sample[int64] {
+		return &model.Sample[int64]{
+			ID:        "a",
+			Type:      model.Counter,
+			Value:     int64(offsetSec),
+			Timestamp: base.Add(time.Duration(offsetSec) * time.Second),
+		}
+	}
+
+	repo := NewMetricInMemRepo[int64](nil)
+	for _, s := range []*model.Sample[int64]{sample(0), sample(3600), sample(3610)} {
+		require.NoError(t, repo.Append(context.Background(), s))
+	}
+
+	tests := []struct {
+		name     string
+		metricID string
+		before   time.Time
+		want     *model.Sample[int64]
+	}{
+		{
+			name:     "Latest sample long before",
+			metricID: "a",
+			before:   base.Add(3000 * time.Second),
+			want:     sample(0),
+		},
+		{
+			name:     "Bound is exclusive",
+			metricID: "a",
+			before:   base.Add(3610 * time.Second),
+			want:     sample(3600),
+		},
+		{
+			name:     "No earlier sample",
+			metricID: "a",
+			before:   base,
+		},
+		{
+			name:     "Unknown metric",
+			metricID: "b",
+			before:   base.Add(time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := repo.LatestBefore(context.Background(), tt.metricID, model.Counter, nil, tt.before)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMetricInMemRepo_DeleteSamplesBefore(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	sample := func(id string, offsetSec int) *model.Sample[int64] {
+		return &model.Sample[int64]{
+			ID:        id,
+			Type:      model.Counter,
+			Value:     int64(offsetSec),
+			Timestamp: base.Add(time.Duration(offsetSec) * time.Second),
+		}
+	}
+
+	repo := NewMetricInMemRepo[int64](nil)
+	for _, s := range []*model.Sample[int64]{sample("a", 0), sample("a", 10), sample("a", 20), sample("b", 5)} {
+		require.NoError(t, repo.Append(context.Background(), s))
+	}
+
+	require.NoError(t, repo.DeleteSamplesBefore(context.Background(), model.Counter, base.Add(10*time.Second)))
+
+	got, err := repo.Range(context.Background(), "a", model.Counter, nil, base, base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []model.Sample[int64]{*sample("a", 10), *sample("a", 20)}, got)
+
+	got, err = repo.Range(context.Background(), "b", model.Counter, nil, base, base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestMetricInMemRepo_labels(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMetricInMemRepo[int64](nil)
+	ctx := context.Background()
+
+	cpu0 := model.Labels{"cpu": "0", "host": "a"}
+	m := &model.Metrics[int64]{ID: "ticks", Type: model.Counter, Value: 1, Labels: cpu0}
+	_, err := repo.Update(ctx, m)
+	require.NoError(t, err)
+	m.Reset()
+
+	_, err = repo.Update(ctx, &model.Metrics[int64]{
+		ID:     "ticks",
+		Type:   model.Counter,
+		Value:  2,
+		Labels: model.Labels{"host": "a", "cpu": "0"},
+	})
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, &model.Metrics[int64]{
+		ID:     "ticks",
+		Type:   model.Counter,
+		Value:  5,
+		Labels: model.Labels{"cpu": "1", "host": "a"},
+	})
+	require.NoError(t, err)
+	_, err = repo.Update(ctx, &model.Metrics[int64]{ID: "ticks", Type: model.Counter, Value: 7})
+	require.NoError(t, err)
+
+	got, err := repo.Get(ctx, "ticks", model.Counter, model.Labels{"cpu": "0", "host": "a"})
+	require.NoError(t, err)
+	assert.False(t, got.UpdatedAt.IsZero())
+	got.UpdatedAt = time.Time{}
+	assert.Equal(t, &model.Metrics[int64]{ID: "ticks", Type: model.Counter, Value: 3, Labels: cpu0}, got)
+
+	got, err = repo.Get(ctx, "ticks", model.Counter, model.Labels{"cpu": "1", "host": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), got.Value)
+
+	got, err = repo.Get(ctx, "ticks", model.Counter, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), got.Value)
+
+	_, err = repo.Get(ctx, "ticks", model.Counter, model.Labels{"cpu": "2"})
+	assert.Error(t, err)
+
+	metrics, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, metrics, 3)
+}
+
+func TestMetricInMemRepo_updatedAt(t *testing.T) {
+	t.Parallel()
+
+	repo := NewMetricInMemRepo(StorageState[float64]{
+		"load": {ID: "load", Type: model.Gauge, Value: 1},
+	})
+	ctx := context.Background()
+
+	got, err := repo.Get(ctx, "load", model.Gauge, nil)
+	require.NoError(t, err)
+	assert.True(t, got.UpdatedAt.IsZero(), "restored metric keeps its update time")
+
+	before := time.Now()
+	require.NoError(t, repo.Set(ctx, &model.Metrics[float64]{ID: "load", Type: model.Gauge, Value: 2}))
+	require.NoError(t, repo.Set(ctx, &model.Metrics[float64]{ID: "cpu", Type: model.Gauge, Value: 3}))
+
+	metrics, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	for _, m := range metrics {
+		assert.False(t, m.UpdatedAt.Before(before), m.ID)
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/yogenyslav/ya-metrics/internal/model"
@@ -21,19 +22,23 @@ func NewMetricPostgresRepo[T int64 | float64](pg database.DB) *MetricPostgresRep
 }
 
 const getGaugeMetric = `
-	select id, mtype, coalesce(delta::double precision, value::double precision) as value
+	select id, mtype, labels, coalesce(delta::double precision, value::double precision) as value, updated_at
 	from metrics
-	where id = $1 and mtype = $2;
+	where id = $1 and mtype = $2 and labels = $3;
 `
 
 const getCounterMetric = `
-	select id, mtype, coalesce(delta::double precision, value::double precision)::bigint as value
+	select id, mtype, labels, coalesce(delta::double precision, value::double precision)::bigint as value, updated_at
 	from metrics
-	where id = $1 and mtype = $2;
+	where id = $1 and mtype = $2 and labels = $3;
 `
 
-// Get retrieves a metric by name.
-func (r *MetricPostgresRepo[T]) Get(ctx context.Context, metricName, metricType string) (*model.Metrics[T], error) {
+// Get retrieves a metric series by name and labels.
+func (r *MetricPostgresRepo[T]) Get(
+	ctx context.Context,
+	metricName, metricType string,
+	labels model.Labels,
+) (*model.Metrics[T], error) {
 	var (
 		m     model.Metrics[T]
 		query string
@@ -45,7 +50,7 @@ func (r *MetricPostgresRepo[T]) Get(ctx context.Context, metricName, metricType
 		query = getCounterMetric
 	}
 
-	err := r.pg.QueryRow(ctx, &m, query, metricName, metricType)
+	err := r.pg.QueryRow(ctx, &m, query, metricName, metricType, labelsArg(labels))
 	if err != nil {
 		return nil, errs.Wrap(err, "failed to query")
 	}
@@ -53,12 +58,12 @@ func (r *MetricPostgresRepo[T]) Get(ctx context.Context, metricName, metricType
 }
 
 const listGaugeMetrics = `
-	select id, mtype, coalesce(delta::double precision, value::double precision) as value
+	select id, mtype, labels, coalesce(delta::double precision, value::double precision) as value, updated_at
 	from metrics;
 `
 
 const listCounterMetrics = `
-	select id, mtype, coalesce(delta::double precision, value::double precision)::bigint as value
+	select id, mtype, labels, coalesce(delta::double precision, value::double precision)::bigint as value, updated_at
 	from metrics;
 `
 
@@ -83,19 +88,20 @@ func (r *MetricPostgresRepo[T]) List(ctx context.Context) ([]model.Metrics[T], e
 }
 
 const setGaugeMetric = `
-	insert into metrics (id, mtype, value)
-	values ($1, $2, $3)
-	on conflict (id) do update set 
-		value = $3,
+	insert into metrics (id, mtype, labels, value)
+	values ($1, $2, $3, $4)
+	on conflict (id, labels) do update set 
+		value = $4,
 		updated_at = current_timestamp;
 `
 
 const updateCounterMetric = `
-	insert into metrics (id, mtype, delta)
-	values ($1, $2, $3)
-	on conflict (id) do update set 
-		delta = metrics.delta + $3,
-		updated_at = current_timestamp;
+	insert into metrics (id, mtype, labels, delta)
+	values ($1, $2, $3, $4)
+	on conflict (id, labels) do update set 
+		delta = metrics.delta + $4,
+		updated_at = current_timestamp
+	returning delta;
 `
 
 func (r *MetricPostgresRepo[T]) setOrUpdate(ctx context.Context, m *model.Metrics[T]) error {
@@ -104,13 +110,11 @@ func (r *MetricPostgresRepo[T]) setOrUpdate(ctx context.Context, m *model.Metric
 	switch any(m.Value).(type) {
 	case float64:
 		query = setGaugeMetric
-	case int64:
-		query = updateCounterMetric
 	default:
 		return errs.Wrap(errs.ErrInvalidMetricType, "unsupported metric type")
 	}
 
-	rowsCount, err := r.pg.Exec(ctx, query, m.ID, m.Type, m.Value)
+	rowsCount, err := r.pg.Exec(ctx, query, m.ID, m.Type, labelsArg(m.Labels), m.Value)
 	if err != nil {
 		return errs.Wrap(err, "failed to exec")
 	}
@@ -125,17 +129,159 @@ func (r *MetricPostgresRepo[T]) setOrUpdate(ctx context.Context, m *model.Metric
 // Set sets the value of a gauge metric.
 func (r *MetricPostgresRepo[T]) Set(ctx context.Context, m *model.Metrics[float64]) error {
 	return r.setOrUpdate(ctx, &model.Metrics[T]{
-		ID:    m.ID,
-		Type:  m.Type,
-		Value: T(m.Value),
+		ID:     m.ID,
+		Type:   m.Type,
+		Value:  T(m.Value),
+		Labels: m.Labels,
 	})
 }
 
-// Update updates the value of a counter metric.
-func (r *MetricPostgresRepo[T]) Update(ctx context.Context, m *model.Metrics[int64]) error {
-	return r.setOrUpdate(ctx, &model.Metrics[T]{
-		ID:    m.ID,
-		Type:  m.Type,
-		Value: T(m.Value),
-	})
+// Update adds the delta to a counter metric and returns the new total.
+func (r *MetricPostgresRepo[T]) Update(ctx context.Context, m *model.Metrics[int64]) (int64, error) {
+	var total int64
+	err := r.pg.QueryRow(ctx, &total, updateCounterMetric, m.ID, m.Type, labelsArg(m.Labels), m.Value)
+	if err != nil {
+		return 0, errs.Wrap(err, "failed to query")
+	}
+	return total, nil
+}
+
+const appendGaugeSample = `
+	insert into metric_samples (id, mtype, labels, ts, value)
+	values ($1, $2, $3, $4, $5)
+	on conflict (id, mtype, labels, ts) do update set
+		value = excluded.value;
+`
+
+const appendCounterSample = `
+	insert into metric_samples (id, mtype, labels, ts, delta)
+	values ($1, $2, $3, $4, $5)
+	on conflict (id, mtype, labels, ts) do update set
+		delta = excluded.delta;
+`
+
+// Append adds a sample to the metric history.
+func (r *MetricPostgresRepo[T]) Append(ctx context.Context, s *model.Sample[T]) error {
+	var query string
+
+	switch any(s.Value).(type) {
+	case float64:
+		query = appendGaugeSample
+	case int64:
+		query = appendCounterSample
+	default:
+		return errs.Wrap(errs.ErrInvalidMetricType, "unsupported metric type")
+	}
+
+	_, err := r.pg.Exec(ctx, query, s.ID, s.Type, labelsArg(s.Labels), s.Timestamp, s.Value)
+	if err != nil {
+		return errs.Wrap(err, "failed to exec")
+	}
+
+	return nil
+}
+
+const rangeGaugeSamples = `
+	select id, mtype, labels, ts, coalesce(delta::double precision, value::double precision) as value
+	from metric_samples
+	where id = $1 and mtype = $2 and labels = $3 and ts between $4 and $5
+	order by ts;
+`
+
+const rangeCounterSamples = `
+	select id, mtype, labels, ts, coalesce(delta::double precision, value::double precision)::bigint as value
+	from metric_samples
+	where id = $1 and mtype = $2 and labels = $3 and ts between $4 and $5
+	order by ts;
+`
+
+// Range retrieves metric samples with timestamps in [from, to] ordered by timestamp.
+func (r *MetricPostgresRepo[T]) Range(
+	ctx context.Context,
+	metricName, metricType string,
+	labels model.Labels,
+	from, to time.Time,
+) ([]model.Sample[T], error) {
+	var (
+		samples []model.Sample[T]
+		query   string
+	)
+
+	if reflect.TypeFor[T]().Kind() == reflect.Float64 {
+		query = rangeGaugeSamples
+	} else {
+		query = rangeCounterSamples
+	}
+
+	err := r.pg.QuerySlice(ctx, &samples, query, metricName, metricType, labelsArg(labels), from, to)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	return samples, nil
+}
+
+const latestGaugeSampleBefore = `
+	select id, mtype, labels, ts, coalesce(delta::double precision, value::double precision) as value
+	from metric_samples
+	where id = $1 and mtype = $2 and labels = $3 and ts < $4
+	order by ts desc
+	limit 1;
+`
+
+const latestCounterSampleBefore = `
+	select id, mtype, labels, ts, coalesce(delta::double precision, value::double precision)::bigint as value
+	from metric_samples
+	where id = $1 and mtype = $2 and labels = $3 and ts < $4
+	order by ts desc
+	limit 1;
+`
+
+// LatestBefore retrieves the latest metric sample recorded before the given time, nil if there is none.
+func (r *MetricPostgresRepo[T]) LatestBefore(
+	ctx context.Context,
+	metricName, metricType string,
+	labels model.Labels,
+	before time.Time,
+) (*model.Sample[T], error) {
+	var (
+		samples []model.Sample[T]
+		query   string
+	)
+
+	if reflect.TypeFor[T]().Kind() == reflect.Float64 {
+		query = latestGaugeSampleBefore
+	} else {
+		query = latestCounterSampleBefore
+	}
+
+	err := r.pg.QuerySlice(ctx, &samples, query, metricName, metricType, labelsArg(labels), before)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	if len(samples) == 0 {
+		return nil, nil
+	}
+	return &samples[0], nil
+}
+
+const deleteSamplesBefore = `
+	delete from metric_samples
+	where mtype = $1 and ts < $2;
+`
+
+// DeleteSamplesBefore removes samples of all series of the metric type recorded before the given time.
+func (r *MetricPostgresRepo[T]) DeleteSamplesBefore(ctx context.Context, metricType string, before time.Time) error {
+	_, err := r.pg.Exec(ctx, deleteSamplesBefore, metricType, before)
+	if err != nil {
+		return errs.Wrap(err, "failed to exec")
+	}
+	return nil
+}
+
+// labelsArg makes an empty label set stored as '{}' rather than json null, so it takes part in series identity.
+func labelsArg(labels model.Labels) model.Labels {
+	if labels == nil {
+		return model.Labels{}
+	}
+	return labels
 }
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/database"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// HistogramPostgresRepo is a histogram repository for pg.
+type HistogramPostgresRepo struct {
+	pg database.DB
+}
+
+// NewHistogramPostgresRepo creates a new HistogramPostgresRepo.
+func NewHistogramPostgresRepo(pg database.DB) *HistogramPostgresRepo {
+	return &HistogramPostgresRepo{pg: pg}
+}
+
+const getHistogram = `
+	select id, labels, bounds, counts, count, sum, updated_at
+	from histograms
+	where id = $1 and labels = $2;
+`
+
+// Get retrieves a histogram series by name and labels.
+func (r *HistogramPostgresRepo) Get(
+	ctx context.Context,
+	metricName string,
+	labels model.Labels,
+) (*model.HistogramMetric, error) {
+	var h model.HistogramMetric
+
+	err := r.pg.QueryRow(ctx, &h, getHistogram, metricName, labelsArg(labels))
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	return &h, nil
+}
+
+const listHistograms = `
+	select id, labels, bounds, counts, count, sum, updated_at
+	from histograms;
+`
+
+// List retrieves all histograms.
+func (r *HistogramPostgresRepo) List(ctx context.Context) ([]model.HistogramMetric, error) {
+	var histograms []model.HistogramMetric
+
+	err := r.pg.QuerySlice(ctx, &histograms, listHistograms)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	return histograms, nil
+}
+
+// mergeHistogram adds bucket counts element-wise, the update is skipped when bounds differ.
+const mergeHistogram = `
+	insert into histograms (id, labels, bounds, counts, count, sum)
+	values ($1, $2, $3, $4, $5, $6)
+	on conflict (id, labels) do update set
+		counts = (
+			select array_agg(stored + added order by idx)
+			from unnest(histograms.counts, excluded.counts) with ordinality as c(stored, added, idx)
+		),
+		count = histograms.count + excluded.count,
+		sum = histograms.sum + excluded.sum,
+		updated_at = current_timestamp
+	where histograms.bounds = excluded.bounds;
+`
+
+// Merge adds observations to the histogram series, buckets of an existing series must match.
+func (r *HistogramPostgresRepo) Merge(ctx context.Context, h *model.HistogramMetric) error {
+	rowsCount, err := r.pg.Exec(
+		ctx, mergeHistogram,
+		h.ID, labelsArg(h.Labels), h.Bounds, h.Counts, h.Count, h.Sum,
+	)
+	if err != nil {
+		return errs.Wrap(err, "failed to exec")
+	}
+
+	if rowsCount == 0 {
+		return errs.Wrap(errs.ErrBucketsMismatch, h.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestHistogramPostgresRepo_Merge(t *testing.T) {
+	t.Parallel()
+
+	h := &model.HistogramMetric{
+		ID: "latency",
+		HistogramData: model.HistogramData{
+			Bounds: []float64{0.1, 1},
+			Counts: []int64{1, 2, 0},
+			Count:  3,
+			Sum:    1.5,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		rowsCount int64
+		execErr   error
+		wantErr   error
+	}{
+		{name: "Merge success", rowsCount: 1},
+		{name: "Merge with different buckets", rowsCount: 0, wantErr: errs.ErrBucketsMismatch},
+		{name: "Merge exec error", execErr: errors.New("db error"), wantErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB := mocks.NewMockDB(gomock.NewController(t))
+			mockDB.EXPECT().
+				Exec(gomock.Any(), mergeHistogram, "latency", model.Labels{}, h.Bounds, h.Counts, h.Count, h.Sum).
+				Return(tt.rowsCount, tt.execErr)
+
+			err := NewHistogramPostgresRepo(mockDB).Merge(context.Background(), h)
+			switch {
+			case tt.wantErr == nil:
+				require.NoError(t, err)
+			case tt.execErr != nil:
+				require.Error(t, err)
+			default:
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHistogramPostgresRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	mockDB := mocks.NewMockDB(gomock.NewController(t))
+	mockDB.EXPECT().
+		QueryRow(gomock.Any(), gomock.Any(), getHistogram, "latency", model.Labels{"path": "/"}).
+		DoAndReturn(func(_ context.Context, dest any, _ string, _ ...any) error {
+			d := dest.(*model.HistogramMetric)
+			d.ID = "latency"
+			d.Labels = model.Labels{"path": "/"}
+			d.HistogramData = model.HistogramData{Bounds: []float64{1}, Counts: []int64{2, 1}, Count: 3, Sum: 2}
+			return nil
+		})
+
+	got, err := NewHistogramPostgresRepo(mockDB).Get(context.Background(), "latency", model.Labels{"path": "/"})
+	require.NoError(t, err)
+	assert.Equal(t, &model.HistogramMetric{
+		ID:            "latency",
+		Labels:        model.Labels{"path": "/"},
+		HistogramData: model.HistogramData{Bounds: []float64{1}, Counts: []int64{2, 1}, Count: 3, Sum: 2},
+	}, got)
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/database"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// RollupPostgresRepo is a repository for downsampled metric history in pg.
+type RollupPostgresRepo struct {
+	pg database.DB
+}
+
+// NewRollupPostgresRepo creates a new RollupPostgresRepo.
+func NewRollupPostgresRepo(pg database.DB) *RollupPostgresRepo {
+	return &RollupPostgresRepo{pg: pg}
+}
+
+const saveRollup = `
+	insert into metric_rollups (id, mtype, labels, resolution, ts, count, min, max, avg, last, sum, rate)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	on conflict (id, mtype, labels, resolution, ts) do update set
+		count = excluded.count,
+		min = excluded.min,
+		max = excluded.max,
+		avg = excluded.avg,
+		last = excluded.last,
+		sum = excluded.sum,
+		rate = excluded.rate;
+`
+
+// Save stores rollups replacing the ones computed earlier for the same interval.
+func (r *RollupPostgresRepo) Save(ctx context.Context, rollups ...model.Rollup) error {
+	for _, rollup := range rollups {
+		_, err := r.pg.Exec(
+			ctx,
+			saveRollup,
+			rollup.ID,
+			rollup.Type,
+			labelsArg(rollup.Labels),
+			rollup.Resolution,
+			rollup.Timestamp,
+			rollup.Count,
+			rollup.Min,
+			rollup.Max,
+			rollup.Avg,
+			rollup.Last,
+			rollup.Sum,
+			rollup.Rate,
+		)
+		if err != nil {
+			return errs.Wrap(err, "failed to exec")
+		}
+	}
+	return nil
+}
+
+const rangeRollups = `
+	select id, mtype, labels, resolution, ts, count, min, max, avg, last, sum, rate
+	from metric_rollups
+	where id = $1 and mtype = $2 and labels = $3 and resolution = $4 and ts between $5 and $6
+	order by ts;
+`
+
+// Range retrieves rollups of the given resolution in seconds with timestamps in [from, to] ordered by timestamp.
+func (r *RollupPostgresRepo) Range(
+	ctx context.Context,
+	metricID, metricType string,
+	labels model.Labels,
+	resolution int64,
+	from, to time.Time,
+) ([]model.Rollup, error) {
+	var rollups []model.Rollup
+
+	err := r.pg.QuerySlice(ctx, &rollups, rangeRollups, metricID, metricType, labelsArg(labels), resolution, from, to)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	return rollups, nil
+}
+
+const deleteRollupsBefore = `
+	delete from metric_rollups
+	where resolution = $1 and ts < $2;
+`
+
+// DeleteBefore removes rollups of the given resolution in seconds recorded before the given time.
+func (r *RollupPostgresRepo) DeleteBefore(ctx context.Context, resolution int64, before time.Time) error {
+	_, err := r.pg.Exec(ctx, deleteRollupsBefore, resolution, before)
+	if err != nil {
+		return errs.Wrap(err, "failed to exec")
+	}
+	return nil
+}
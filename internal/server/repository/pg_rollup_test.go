@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestRollupPostgresRepo_Save(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	rollup := model.Rollup{
+		ID:         "requests",
+		Type:       model.Counter,
+		Resolution: 60,
+		Timestamp:  ts,
+		Count:      3,
+		Last:       12,
+		Sum:        6,
+		Rate:       0.1,
+	}
+
+	tests := []struct {
+		name    string
+		execErr error
+	}{
+		{name: "Save success"},
+		{name: "Save exec error", execErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB := mocks.NewMockDB(gomock.NewController(t))
+			mockDB.EXPECT().
+				Exec(
+					gomock.Any(), saveRollup,
+					"requests", model.Counter, model.Labels{}, int64(60), ts,
+					int64(3), 0.0, 0.0, 0.0, 12.0, 6.0, 0.1,
+				).
+				Return(int64(1), tt.execErr)
+
+			err := NewRollupPostgresRepo(mockDB).Save(context.Background(), rollup)
+			if tt.execErr != nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRollupPostgresRepo_DeleteBefore(t *testing.T) {
+	t.Parallel()
+
+	before := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	mockDB := mocks.NewMockDB(gomock.NewController(t))
+	mockDB.EXPECT().Exec(gomock.Any(), deleteRollupsBefore, int64(3600), before).Return(int64(5), nil)
+
+	require.NoError(t, NewRollupPostgresRepo(mockDB).DeleteBefore(context.Background(), 3600, before))
+}
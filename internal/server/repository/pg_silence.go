@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/database"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// SilencePostgresRepo is a repository for alert silences in pg.
+type SilencePostgresRepo struct {
+	pg database.DB
+}
+
+// NewSilencePostgresRepo creates a new SilencePostgresRepo.
+func NewSilencePostgresRepo(pg database.DB) *SilencePostgresRepo {
+	return &SilencePostgresRepo{pg: pg}
+}
+
+const createSilence = `
+	insert into silences (id, metric_id, labels, starts_at, ends_at, comment, created_at)
+	values ($1, $2, $3, $4, $5, $6, $7);
+`
+
+// Create stores a new silence.
+func (r *SilencePostgresRepo) Create(ctx context.Context, silence model.Silence) error {
+	_, err := r.pg.Exec(
+		ctx,
+		createSilence,
+		silence.ID,
+		silence.MetricID,
+		labelsArg(silence.Labels),
+		silence.StartsAt,
+		silence.EndsAt,
+		silence.Comment,
+		silence.CreatedAt,
+	)
+	if err != nil {
+		return errs.Wrap(err, "failed to exec")
+	}
+	return nil
+}
+
+const listSilences = `
+	select id, metric_id, labels, starts_at, ends_at, comment, created_at
+	from silences
+	order by created_at, id;
+`
+
+// List returns all silences ordered by creation time.
+func (r *SilencePostgresRepo) List(ctx context.Context) ([]model.Silence, error) {
+	var silences []model.Silence
+
+	err := r.pg.QuerySlice(ctx, &silences, listSilences)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	return silences, nil
+}
+
+const expireSilence = `
+	update silences
+	set ends_at = least(ends_at, $2)
+	where id = $1;
+`
+
+// Expire ends the silence at the given time unless it has already ended.
+func (r *SilencePostgresRepo) Expire(ctx context.Context, id string, now time.Time) error {
+	rows, err := r.pg.Exec(ctx, expireSilence, id, now)
+	if err != nil {
+		return errs.Wrap(err, "failed to exec")
+	}
+	if rows == 0 {
+		return errs.Wrap(errs.ErrSilenceNotFound, id)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestSilencePostgresRepo_Create(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	silence := model.Silence{
+		ID:        "s1",
+		MetricID:  "load",
+		StartsAt:  ts,
+		EndsAt:    ts.Add(time.Hour),
+		Comment:   "maintenance",
+		CreatedAt: ts,
+	}
+
+	tests := []struct {
+		name    string
+		execErr error
+	}{
+		{name: "Create success"},
+		{name: "Create exec error", execErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB := mocks.NewMockDB(gomock.NewController(t))
+			mockDB.EXPECT().
+				Exec(
+					gomock.Any(), createSilence,
+					"s1", "load", model.Labels{}, ts, ts.Add(time.Hour), "maintenance", ts,
+				).
+				Return(int64(1), tt.execErr)
+
+			err := NewSilencePostgresRepo(mockDB).Create(context.Background(), silence)
+			if tt.execErr != nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSilencePostgresRepo_Expire(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		rows    int64
+		wantErr error
+	}{
+		{name: "Expire success", rows: 1},
+		{name: "Expire unknown silence", wantErr: errs.ErrSilenceNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB := mocks.NewMockDB(gomock.NewController(t))
+			mockDB.EXPECT().Exec(gomock.Any(), expireSilence, "s1", now).Return(tt.rows, nil)
+
+			err := NewSilencePostgresRepo(mockDB).Expire(context.Background(), "s1", now)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/database"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/pkg/sketch"
+)
+
+// SummaryPostgresRepo is a summary repository for pg.
+type SummaryPostgresRepo struct {
+	pg database.DB
+}
+
+// NewSummaryPostgresRepo creates a new SummaryPostgresRepo.
+func NewSummaryPostgresRepo(pg database.DB) *SummaryPostgresRepo {
+	return &SummaryPostgresRepo{pg: pg}
+}
+
+const getSummary = `
+	select id, labels, sketch, updated_at
+	from summaries
+	where id = $1 and labels = $2;
+`
+
+// Get retrieves a summary series by name and labels.
+func (r *SummaryPostgresRepo) Get(
+	ctx context.Context,
+	metricName string,
+	labels model.Labels,
+) (*model.SummaryMetric, error) {
+	var s model.SummaryMetric
+
+	err := r.pg.QueryRow(ctx, &s, getSummary, metricName, labelsArg(labels))
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	return &s, nil
+}
+
+const listSummaries = `
+	select id, labels, sketch, updated_at
+	from summaries;
+`
+
+// List retrieves all summaries.
+func (r *SummaryPostgresRepo) List(ctx context.Context) ([]model.SummaryMetric, error) {
+	var summaries []model.SummaryMetric
+
+	err := r.pg.QuerySlice(ctx, &summaries, listSummaries)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to query")
+	}
+	return summaries, nil
+}
+
+// mergeSummary merges sketches in place the same way sketch.Sketch.Merge does,
+// the update is skipped when relative accuracy differs.
+const mergeSummary = `
+	insert into summaries (id, labels, sketch)
+	values ($1, $2, $3)
+	on conflict (id, labels) do update set
+		sketch = jsonb_build_object(
+			'alpha', summaries.sketch->'alpha',
+			'positive', (
+				select coalesce(jsonb_object_agg(b.idx, b.total), '{}'::jsonb)
+				from (
+					select key as idx, sum(value::bigint) as total
+					from (
+						select * from jsonb_each_text(coalesce(summaries.sketch->'positive', '{}'::jsonb))
+						union all
+						select * from jsonb_each_text(coalesce(excluded.sketch->'positive', '{}'::jsonb))
+					) buckets
+					group by key
+				) b
+			),
+			'negative', (
+				select coalesce(jsonb_object_agg(b.idx, b.total), '{}'::jsonb)
+				from (
+					select key as idx, sum(value::bigint) as total
+					from (
+						select * from jsonb_each_text(coalesce(summaries.sketch->'negative', '{}'::jsonb))
+						union all
+						select * from jsonb_each_text(coalesce(excluded.sketch->'negative', '{}'::jsonb))
+					) buckets
+					group by key
+				) b
+			),
+			'zero', coalesce((summaries.sketch->>'zero')::bigint, 0) + coalesce((excluded.sketch->>'zero')::bigint, 0),
+			'count', (summaries.sketch->>'count')::bigint + (excluded.sketch->>'count')::bigint,
+			'sum', (summaries.sketch->>'sum')::double precision + (excluded.sketch->>'sum')::double precision,
+			'min', least((summaries.sketch->>'min')::double precision, (excluded.sketch->>'min')::double precision),
+			'max', greatest((summaries.sketch->>'max')::double precision, (excluded.sketch->>'max')::double precision)
+		),
+		updated_at = current_timestamp
+	where summaries.sketch->'alpha' = excluded.sketch->'alpha';
+`
+
+// Merge adds observations recorded by the sketch of s to the summary series.
+func (r *SummaryPostgresRepo) Merge(ctx context.Context, s *model.SummaryMetric) error {
+	rowsCount, err := r.pg.Exec(ctx, mergeSummary, s.ID, labelsArg(s.Labels), s.Sketch)
+	if err != nil {
+		return errs.Wrap(err, "failed to exec")
+	}
+
+	if rowsCount == 0 {
+		return errs.Wrap(sketch.ErrAccuracyMismatch, s.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/sketch"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestSummaryPostgresRepo_Merge(t *testing.T) {
+	t.Parallel()
+
+	s := model.NewSummaryMetric("latency", nil, 0.5, 1.5)
+
+	tests := []struct {
+		name      string
+		rowsCount int64
+		execErr   error
+		wantErr   error
+	}{
+		{name: "Merge success", rowsCount: 1},
+		{name: "Merge with different accuracy", rowsCount: 0, wantErr: sketch.ErrAccuracyMismatch},
+		{name: "Merge exec error", execErr: errors.New("db error"), wantErr: errors.New("db error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockDB := mocks.NewMockDB(gomock.NewController(t))
+			mockDB.EXPECT().
+				Exec(gomock.Any(), mergeSummary, "latency", model.Labels{}, s.Sketch).
+				Return(tt.rowsCount, tt.execErr)
+
+			err := NewSummaryPostgresRepo(mockDB).Merge(context.Background(), s)
+			switch {
+			case tt.wantErr == nil:
+				require.NoError(t, err)
+			case tt.execErr != nil:
+				require.Error(t, err)
+			default:
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSummaryPostgresRepo_Get(t *testing.T) {
+	t.Parallel()
+
+	want := model.NewSummaryMetric("latency", model.Labels{"path": "/"}, 1, 2)
+
+	mockDB := mocks.NewMockDB(gomock.NewController(t))
+	mockDB.EXPECT().
+		QueryRow(gomock.Any(), gomock.Any(), getSummary, "latency", model.Labels{"path": "/"}).
+		DoAndReturn(func(_ context.Context, dest any, _ string, _ ...any) error {
+			*dest.(*model.SummaryMetric) = *want
+			return nil
+		})
+
+	got, err := NewSummaryPostgresRepo(mockDB).Get(context.Background(), "latency", model.Labels{"path": "/"})
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
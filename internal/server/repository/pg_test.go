@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -35,7 +36,7 @@ func TestMetricPostgresRepo_Get(t *testing.T) {
 				db: func() *mocks.MockDB {
 					mockDB := mocks.NewMockDB(ctrl)
 					mockDB.EXPECT().
-						QueryRow(gomock.Any(), gomock.Any(), getCounterMetric, gomock.Any(), gomock.Any()).
+						QueryRow(gomock.Any(), gomock.Any(), getCounterMetric, gomock.Any(), gomock.Any(), gomock.Any()).
 						DoAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
 							d := dest.(*model.Metrics[int64])
 							d.ID = "metric1"
@@ -54,7 +55,7 @@ func TestMetricPostgresRepo_Get(t *testing.T) {
 				db: func() *mocks.MockDB {
 					mockDB := mocks.NewMockDB(ctrl)
 					mockDB.EXPECT().
-						QueryRow(gomock.Any(), gomock.Any(), getCounterMetric, gomock.Any(), gomock.Any()).
+						QueryRow(gomock.Any(), gomock.Any(), getCounterMetric, gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(errors.New("not found"))
 					return mockDB
 				},
@@ -69,7 +70,7 @@ func TestMetricPostgresRepo_Get(t *testing.T) {
 				t.Parallel()
 
 				repo := NewMetricPostgresRepo[int64](tt.db())
-				got, err := repo.Get(context.Background(), tt.metricName, model.Counter)
+				got, err := repo.Get(context.Background(), tt.metricName, model.Counter, nil)
 
 				if tt.wantErr {
 					require.Error(t, err)
@@ -93,7 +94,7 @@ func TestMetricPostgresRepo_Get(t *testing.T) {
 				db: func() *mocks.MockDB {
 					mockDB := mocks.NewMockDB(ctrl)
 					mockDB.EXPECT().
-						QueryRow(gomock.Any(), gomock.Any(), getGaugeMetric, gomock.Any(), gomock.Any()).
+						QueryRow(gomock.Any(), gomock.Any(), getGaugeMetric, gomock.Any(), gomock.Any(), gomock.Any()).
 						DoAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
 							d := dest.(*model.Metrics[float64])
 							d.ID = "metric1"
@@ -112,7 +113,7 @@ func TestMetricPostgresRepo_Get(t *testing.T) {
 				db: func() *mocks.MockDB {
 					mockDB := mocks.NewMockDB(ctrl)
 					mockDB.EXPECT().
-						QueryRow(gomock.Any(), gomock.Any(), getGaugeMetric, gomock.Any(), gomock.Any()).
+						QueryRow(gomock.Any(), gomock.Any(), getGaugeMetric, gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(errors.New("not found"))
 					return mockDB
 				},
@@ -127,7 +128,7 @@ func TestMetricPostgresRepo_Get(t *testing.T) {
 				t.Parallel()
 
 				repo := NewMetricPostgresRepo[float64](tt.db())
-				got, err := repo.Get(context.Background(), tt.metricName, model.Gauge)
+				got, err := repo.Get(context.Background(), tt.metricName, model.Gauge, nil)
 
 				if tt.wantErr {
 					require.Error(t, err)
@@ -296,7 +297,7 @@ func TestMetricPostgresRepo_Set(t *testing.T) {
 			db: func() *mocks.MockDB {
 				mockDB := mocks.NewMockDB(ctrl)
 				mockDB.EXPECT().
-					Exec(gomock.Any(), setGaugeMetric, gomock.Any(), gomock.Any(), gomock.Any()).
+					Exec(gomock.Any(), setGaugeMetric, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(int64(1), nil)
 				return mockDB
 			},
@@ -308,7 +309,7 @@ func TestMetricPostgresRepo_Set(t *testing.T) {
 			db: func() *mocks.MockDB {
 				mockDB := mocks.NewMockDB(ctrl)
 				mockDB.EXPECT().
-					Exec(gomock.Any(), setGaugeMetric, gomock.Any(), gomock.Any(), gomock.Any()).
+					Exec(gomock.Any(), setGaugeMetric, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(int64(0), errors.New("db error"))
 				return mockDB
 			},
@@ -343,6 +344,7 @@ func TestMetricPostgresRepo_Update(t *testing.T) {
 		name      string
 		db        func() *mocks.MockDB
 		metric    *model.Metrics[int64]
+		wantTotal int64
 		wantError bool
 	}{
 		{
@@ -350,11 +352,18 @@ func TestMetricPostgresRepo_Update(t *testing.T) {
 			db: func() *mocks.MockDB {
 				mockDB := mocks.NewMockDB(ctrl)
 				mockDB.EXPECT().
-					Exec(gomock.Any(), updateCounterMetric, gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(int64(1), nil)
+					QueryRow(
+						gomock.Any(), gomock.Any(), updateCounterMetric,
+						gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+					).
+					DoAndReturn(func(_ context.Context, dst any, _ string, _ ...any) error {
+						*dst.(*int64) = 25
+						return nil
+					})
 				return mockDB
 			},
 			metric:    &model.Metrics[int64]{ID: "metric1", Type: model.Counter, Value: 10},
+			wantTotal: 25,
 			wantError: false,
 		},
 		{
@@ -362,8 +371,11 @@ func TestMetricPostgresRepo_Update(t *testing.T) {
 			db: func() *mocks.MockDB {
 				mockDB := mocks.NewMockDB(ctrl)
 				mockDB.EXPECT().
-					Exec(gomock.Any(), updateCounterMetric, gomock.Any(), gomock.Any(), gomock.Any()).
-					Return(int64(0), errors.New("db error"))
+					QueryRow(
+						gomock.Any(), gomock.Any(), updateCounterMetric,
+						gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+					).
+					Return(errors.New("db error"))
 				return mockDB
 			},
 			metric:    &model.Metrics[int64]{ID: "metric2", Type: model.Counter, Value: 20},
@@ -376,13 +388,114 @@ func TestMetricPostgresRepo_Update(t *testing.T) {
 			t.Parallel()
 
 			repo := NewMetricPostgresRepo[int64](tt.db())
-			err := repo.Update(context.Background(), tt.metric)
+			total, err := repo.Update(context.Background(), tt.metric)
 
 			if tt.wantError {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
+				assert.Equal(t, tt.wantTotal, total)
 			}
 		})
 	}
 }
+
+func TestMetricPostgresRepo_Append(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ts := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Append gauge sample", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := mocks.NewMockDB(ctrl)
+		mockDB.EXPECT().
+			Exec(gomock.Any(), appendGaugeSample, "metric1", model.Gauge, model.Labels{}, ts, 1.5).
+			Return(int64(1), nil)
+
+		repo := NewMetricPostgresRepo[float64](mockDB)
+		err := repo.Append(context.Background(), &model.Sample[float64]{
+			ID: "metric1", Type: model.Gauge, Value: 1.5, Timestamp: ts,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Append counter sample with DB error", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := mocks.NewMockDB(ctrl)
+		mockDB.EXPECT().
+			Exec(gomock.Any(), appendCounterSample, "metric1", model.Counter, model.Labels{}, ts, int64(3)).
+			Return(int64(0), errors.New("db error"))
+
+		repo := NewMetricPostgresRepo[int64](mockDB)
+		err := repo.Append(context.Background(), &model.Sample[int64]{
+			ID: "metric1", Type: model.Counter, Value: 3, Timestamp: ts,
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestMetricPostgresRepo_Range(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	want := []model.Sample[int64]{
+		{ID: "metric1", Type: model.Counter, Value: 1, Timestamp: from},
+		{ID: "metric1", Type: model.Counter, Value: 4, Timestamp: to},
+	}
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockDB.EXPECT().
+		QuerySlice(gomock.Any(), gomock.Any(), rangeCounterSamples, "metric1", model.Counter, model.Labels{}, from, to).
+		DoAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
+			d := dest.(*[]model.Sample[int64])
+			*d = want
+			return nil
+		})
+
+	repo := NewMetricPostgresRepo[int64](mockDB)
+	got, err := repo.Range(context.Background(), "metric1", model.Counter, nil, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestMetricPostgresRepo_LatestBefore(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	before := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	want := model.Sample[int64]{ID: "metric1", Type: model.Counter, Value: 4, Timestamp: before.Add(-time.Hour)}
+
+	mockDB := mocks.NewMockDB(ctrl)
+	mockDB.EXPECT().
+		QuerySlice(gomock.Any(), gomock.Any(), latestCounterSampleBefore, "metric1", model.Counter, model.Labels{},
+			before).
+		DoAndReturn(func(ctx context.Context, dest any, query string, args ...any) error {
+			d := dest.(*[]model.Sample[int64])
+			*d = []model.Sample[int64]{want}
+			return nil
+		})
+	mockDB.EXPECT().
+		QuerySlice(gomock.Any(), gomock.Any(), latestCounterSampleBefore, "metric2", model.Counter, model.Labels{},
+			before).
+		Return(nil)
+
+	repo := NewMetricPostgresRepo[int64](mockDB)
+	got, err := repo.LatestBefore(context.Background(), "metric1", model.Counter, nil, before)
+	require.NoError(t, err)
+	assert.Equal(t, &want, got)
+
+	got, err = repo.LatestBefore(context.Background(), "metric2", model.Counter, nil, before)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
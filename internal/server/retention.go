@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+const retentionInterval = time.Minute
+
+// RetentionApplier downsamples and expires metric history.
+type RetentionApplier interface {
+	ApplyRetention(ctx context.Context, now time.Time) error
+}
+
+// Start applying the history retention policy.
+func (s *Server) Retention(ctx context.Context, applier RetentionApplier, newTicker TickerFactory) {
+	if !s.cfg.History.Enabled || s.cfg.History.Retention == nil {
+		return
+	}
+
+	ticker := newTicker(retentionInterval)
+
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		defer ticker.Stop()
+
+		var err error
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C():
+				err = applier.ApplyRetention(context.Background(), now)
+				if err != nil {
+					log.Err(errs.Wrap(err)).Msg("apply history retention")
+				}
+			}
+		}
+	}()
+}
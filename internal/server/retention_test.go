@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yogenyslav/ya-metrics/internal/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+type mockRetentionApplier struct {
+	mock.Mock
+
+	called chan struct{}
+}
+
+func (a *mockRetentionApplier) ApplyRetention(ctx context.Context, now time.Time) error {
+	args := a.Called(ctx, now)
+	a.called <- struct{}{}
+	return args.Error(0)
+}
+
+func TestServer_Retention(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticker := &testTicker{ch: make(chan time.Time)}
+	getTicker := func(time.Duration) Ticker { return ticker }
+	applier := &mockRetentionApplier{called: make(chan struct{}, 1)}
+	now := time.Now()
+
+	applier.On("ApplyRetention", mock.Anything, now).Return(nil)
+
+	s := &Server{
+		cfg: &config.Config{
+			History: &config.HistoryConfig{
+				Enabled:   true,
+				Retention: &model.RetentionPolicy{Raw: time.Hour},
+			},
+		},
+	}
+	s.Retention(ctx, applier, getTicker)
+
+	ticker.ch <- now
+
+	select {
+	case <-applier.called:
+		// ok
+	case <-time.After(time.Second):
+		t.Error("retention was not applied within expected interval")
+	}
+}
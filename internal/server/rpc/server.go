@@ -0,0 +1,200 @@
+// Package rpc serves the gRPC API of the server.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/internal/server/audit"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type metricService interface {
+	UpdateMetric(ctx context.Context, metric *model.MetricsDto) error
+	UpdateMetricsBatch(ctx context.Context, metrics []*model.MetricsDto) error
+	GetMetric(ctx context.Context, metricType, metricID string, labels model.Labels) (*model.MetricsDto, error)
+	ListMetrics(ctx context.Context) ([]*model.MetricsDto, error)
+}
+
+type auditLogger interface {
+	LogMetrics(ctx context.Context, metrics []string, ipAddr string) error
+}
+
+// Server implements the Metrics gRPC service on top of the metric service used by HTTP handlers.
+type Server struct {
+	pb.UnimplementedMetricsServer
+
+	ms    metricService
+	audit auditLogger
+}
+
+// NewServer creates a new Server instance.
+func NewServer(ms metricService, audit auditLogger) *Server {
+	return &Server{
+		ms:    ms,
+		audit: audit,
+	}
+}
+
+// Register registers the service on the gRPC server.
+func (s *Server) Register(srv *grpc.Server) {
+	pb.RegisterMetricsServer(srv, s)
+}
+
+// Update updates a single metric.
+func (s *Server) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.UpdateResponse, error) {
+	if req.GetMetric() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no metric provided")
+	}
+
+	metrics, err := s.receive(ctx, []*pb.Metric{req.GetMetric()})
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	if err := s.ms.UpdateMetric(ctx, metrics[0]); err != nil {
+		return nil, statusError(err)
+	}
+	if err := s.logMetrics(ctx, metrics); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.UpdateResponse{}, nil
+}
+
+// UpdateBatch updates a batch of metrics in a single transaction.
+func (s *Server) UpdateBatch(ctx context.Context, req *pb.UpdateBatchRequest) (*pb.UpdateBatchResponse, error) {
+	if err := s.updateBatch(ctx, req.GetMetrics()); err != nil {
+		return nil, statusError(err)
+	}
+	return &pb.UpdateBatchResponse{}, nil
+}
+
+// Get returns a metric series by its type, name and labels.
+func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	labels := model.Labels(req.GetLabels())
+	if !labels.Valid() {
+		return nil, statusError(errs.ErrInvalidLabels)
+	}
+
+	metric, err := s.ms.GetMetric(ctx, pb.ModelType(req.GetType()), req.GetId(), labels)
+	if err != nil {
+		return nil, statusError(errs.Wrap(errs.ErrMetricNotFound, err.Error()))
+	}
+	return &pb.GetResponse{Metric: pb.FromDto(metric)}, nil
+}
+
+// List returns all metric series.
+func (s *Server) List(ctx context.Context, _ *pb.ListRequest) (*pb.ListResponse, error) {
+	metrics, err := s.ms.ListMetrics(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &pb.ListResponse{Metrics: make([]*pb.Metric, 0, len(metrics))}
+	for _, m := range metrics {
+		resp.Metrics = append(resp.Metrics, pb.FromDto(m))
+	}
+	return resp, nil
+}
+
+// Push updates every received batch of metrics in its own transaction until the client closes the stream.
+//
+// The response holds the number of metrics received, batches applied before a failure stay applied.
+func (s *Server) Push(stream grpc.ClientStreamingServer[pb.PushRequest, pb.PushResponse]) error {
+	var received int64
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&pb.PushResponse{Received: received})
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := s.updateBatch(stream.Context(), req.GetMetrics()); err != nil {
+			return statusError(err)
+		}
+		received += int64(len(req.GetMetrics()))
+	}
+}
+
+func (s *Server) updateBatch(ctx context.Context, batch []*pb.Metric) error {
+	metrics, err := s.receive(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ms.UpdateMetricsBatch(ctx, metrics); err != nil {
+		return err
+	}
+	return s.logMetrics(ctx, metrics)
+}
+
+// receive validates received metrics and labels them with the identity of the agent which sent them.
+func (s *Server) receive(ctx context.Context, batch []*pb.Metric) ([]*model.MetricsDto, error) {
+	var instance string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(pb.InstanceMetadata); len(values) > 0 {
+			instance = values[0]
+		}
+	}
+
+	metrics := make([]*model.MetricsDto, 0, len(batch))
+	for _, m := range batch {
+		metric := pb.ToDto(m)
+		if metric.ID == "" {
+			return nil, errs.Wrap(errs.ErrNoMetricID)
+		}
+		if !metric.Labels.Valid() {
+			return nil, errs.Wrap(errs.ErrInvalidLabels)
+		}
+		if !hasValue(metric) {
+			return nil, errs.Wrap(errs.ErrInvalidMetricValue, metric.ID)
+		}
+		if instance != "" {
+			metric.Labels = metric.Labels.With(model.InstanceLabel, instance)
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics, nil
+}
+
+// hasValue reports whether the metric carries the value its type needs, unknown types are left to the service.
+func hasValue(m *model.MetricsDto) bool {
+	switch m.Type {
+	case model.Counter:
+		return m.Delta != nil
+	case model.Gauge, model.Summary:
+		return m.Value != nil
+	case model.Histogram:
+		return m.Histogram != nil
+	default:
+		return true
+	}
+}
+
+func (s *Server) logMetrics(ctx context.Context, metrics []*model.MetricsDto) error {
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			ctx = audit.WithAgent(ctx, info.State.VerifiedChains[0][0].Subject.String())
+		}
+	}
+
+	names := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		names = append(names, m.ID)
+	}
+
+	return errs.Wrap(s.audit.LogMetrics(ctx, names, addr), "log audit")
+}
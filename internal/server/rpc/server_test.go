@@ -0,0 +1,360 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+	gomock "go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+func newClient(t *testing.T, ms metricService, audit auditLogger) pb.MetricsClient {
+	t.Helper()
+
+	lis := bufconn.Listen(bufSize)
+	srv := grpc.NewServer()
+	NewServer(ms, audit).Register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewMetricsClient(conn)
+}
+
+func TestServer_Update(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ms       func() metricService
+		audit    func(t *testing.T) auditLogger
+		instance string
+		metric   *pb.Metric
+		wantCode codes.Code
+	}{
+		{
+			name: "Update gauge",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("UpdateMetric", mock.Anything, &model.MetricsDto{
+					ID:    "Alloc",
+					Type:  model.Gauge,
+					Value: pkg.Ptr(1.5),
+				}).Return(nil)
+				return m
+			},
+			audit: func(t *testing.T) auditLogger {
+				m := mocks.NewMockauditLogger(gomock.NewController(t))
+				m.EXPECT().LogMetrics(gomock.Any(), []string{"Alloc"}, gomock.Any()).Return(nil)
+				return m
+			},
+			metric:   &pb.Metric{Id: "Alloc", Type: pb.MetricType_METRIC_TYPE_GAUGE, Value: pkg.Ptr(1.5)},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Update counter labeled with instance",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("UpdateMetric", mock.Anything, &model.MetricsDto{
+					ID:     "PollCount",
+					Type:   model.Counter,
+					Delta:  pkg.Ptr(int64(3)),
+					Labels: model.Labels{"host": "a", model.InstanceLabel: "agent-1"},
+				}).Return(nil)
+				return m
+			},
+			audit: func(t *testing.T) auditLogger {
+				m := mocks.NewMockauditLogger(gomock.NewController(t))
+				m.EXPECT().LogMetrics(gomock.Any(), []string{"PollCount"}, gomock.Any()).Return(nil)
+				return m
+			},
+			instance: "agent-1",
+			metric: &pb.Metric{
+				Id:     "PollCount",
+				Type:   pb.MetricType_METRIC_TYPE_COUNTER,
+				Delta:  pkg.Ptr(int64(3)),
+				Labels: map[string]string{"host": "a"},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "No metric ID",
+			ms: func() metricService {
+				return new(mocks.MockMetricService)
+			},
+			audit: func(t *testing.T) auditLogger {
+				return mocks.NewMockauditLogger(gomock.NewController(t))
+			},
+			metric:   &pb.Metric{Type: pb.MetricType_METRIC_TYPE_GAUGE, Value: pkg.Ptr(1.5)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid labels",
+			ms: func() metricService {
+				return new(mocks.MockMetricService)
+			},
+			audit: func(t *testing.T) auditLogger {
+				return mocks.NewMockauditLogger(gomock.NewController(t))
+			},
+			metric: &pb.Metric{
+				Id:     "Alloc",
+				Type:   pb.MetricType_METRIC_TYPE_GAUGE,
+				Value:  pkg.Ptr(1.5),
+				Labels: map[string]string{"1host": "a"},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Counter without delta",
+			ms: func() metricService {
+				return new(mocks.MockMetricService)
+			},
+			audit: func(t *testing.T) auditLogger {
+				return mocks.NewMockauditLogger(gomock.NewController(t))
+			},
+			metric:   &pb.Metric{Id: "PollCount", Type: pb.MetricType_METRIC_TYPE_COUNTER},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Gauge without value",
+			ms: func() metricService {
+				return new(mocks.MockMetricService)
+			},
+			audit: func(t *testing.T) auditLogger {
+				return mocks.NewMockauditLogger(gomock.NewController(t))
+			},
+			metric:   &pb.Metric{Id: "Alloc", Type: pb.MetricType_METRIC_TYPE_GAUGE},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Invalid metric type",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("UpdateMetric", mock.Anything, mock.Anything).Return(errs.ErrInvalidMetricType)
+				return m
+			},
+			audit: func(t *testing.T) auditLogger {
+				return mocks.NewMockauditLogger(gomock.NewController(t))
+			},
+			metric:   &pb.Metric{Id: "Alloc", Value: pkg.Ptr(1.5)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "Internal error",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("UpdateMetric", mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+				return m
+			},
+			audit: func(t *testing.T) auditLogger {
+				return mocks.NewMockauditLogger(gomock.NewController(t))
+			},
+			metric:   &pb.Metric{Id: "Alloc", Type: pb.MetricType_METRIC_TYPE_GAUGE, Value: pkg.Ptr(1.5)},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClient(t, tt.ms(), tt.audit(t))
+
+			ctx := context.Background()
+			if tt.instance != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, pb.InstanceMetadata, tt.instance)
+			}
+
+			_, err := client.Update(ctx, &pb.UpdateRequest{Metric: tt.metric})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestServer_UpdateBatch(t *testing.T) {
+	t.Parallel()
+
+	ms := new(mocks.MockMetricService)
+	ms.On("UpdateMetricsBatch", mock.Anything, []*model.MetricsDto{
+		{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.5)},
+		{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr(int64(3))},
+	}).Return(nil)
+
+	audit := mocks.NewMockauditLogger(gomock.NewController(t))
+	audit.EXPECT().LogMetrics(gomock.Any(), []string{"Alloc", "PollCount"}, gomock.Any()).Return(nil)
+
+	client := newClient(t, ms, audit)
+
+	_, err := client.UpdateBatch(context.Background(), &pb.UpdateBatchRequest{
+		Metrics: []*pb.Metric{
+			{Id: "Alloc", Type: pb.MetricType_METRIC_TYPE_GAUGE, Value: pkg.Ptr(1.5)},
+			{Id: "PollCount", Type: pb.MetricType_METRIC_TYPE_COUNTER, Delta: pkg.Ptr(int64(3))},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestServer_Get(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ms       func() metricService
+		req      *pb.GetRequest
+		want     *pb.Metric
+		wantCode codes.Code
+	}{
+		{
+			name: "Get labeled gauge",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("GetMetric", mock.Anything, model.Gauge, "Alloc", model.Labels{"host": "a"}).
+					Return(&model.MetricsDto{
+						ID:     "Alloc",
+						Type:   model.Gauge,
+						Value:  pkg.Ptr(1.5),
+						Labels: model.Labels{"host": "a"},
+					}, nil)
+				return m
+			},
+			req: &pb.GetRequest{
+				Id:     "Alloc",
+				Type:   pb.MetricType_METRIC_TYPE_GAUGE,
+				Labels: map[string]string{"host": "a"},
+			},
+			want: &pb.Metric{
+				Id:     "Alloc",
+				Type:   pb.MetricType_METRIC_TYPE_GAUGE,
+				Value:  pkg.Ptr(1.5),
+				Labels: map[string]string{"host": "a"},
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "Metric not found",
+			ms: func() metricService {
+				m := new(mocks.MockMetricService)
+				m.On("GetMetric", mock.Anything, model.Counter, "PollCount", model.Labels(nil)).
+					Return((*model.MetricsDto)(nil), errs.ErrMetricNotFound)
+				return m
+			},
+			req:      &pb.GetRequest{Id: "PollCount", Type: pb.MetricType_METRIC_TYPE_COUNTER},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClient(t, tt.ms(), nil)
+
+			resp, err := client.Get(context.Background(), tt.req)
+			require.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode != codes.OK {
+				return
+			}
+			assert.Equal(t, tt.want.GetId(), resp.GetMetric().GetId())
+			assert.Equal(t, tt.want.GetType(), resp.GetMetric().GetType())
+			assert.Equal(t, tt.want.GetValue(), resp.GetMetric().GetValue())
+			assert.Equal(t, tt.want.GetLabels(), resp.GetMetric().GetLabels())
+		})
+	}
+}
+
+func TestServer_List(t *testing.T) {
+	t.Parallel()
+
+	ms := new(mocks.MockMetricService)
+	ms.On("ListMetrics", mock.Anything).Return([]*model.MetricsDto{
+		{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.5), Stale: true},
+		{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr(int64(3))},
+	}, nil)
+
+	client := newClient(t, ms, nil)
+
+	resp, err := client.List(context.Background(), &pb.ListRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetMetrics(), 2)
+	assert.Equal(t, "Alloc", resp.GetMetrics()[0].GetId())
+	assert.True(t, resp.GetMetrics()[0].GetStale())
+	assert.Equal(t, int64(3), resp.GetMetrics()[1].GetDelta())
+}
+
+func TestServer_Push(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Batches are updated until the stream is closed", func(t *testing.T) {
+		t.Parallel()
+
+		ms := new(mocks.MockMetricService)
+		ms.On("UpdateMetricsBatch", mock.Anything, []*model.MetricsDto{
+			{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.5)},
+			{ID: "Frees", Type: model.Gauge, Value: pkg.Ptr(2.5)},
+		}).Return(nil)
+		ms.On("UpdateMetricsBatch", mock.Anything, []*model.MetricsDto{
+			{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr(int64(3))},
+		}).Return(nil)
+
+		audit := mocks.NewMockauditLogger(gomock.NewController(t))
+		audit.EXPECT().LogMetrics(gomock.Any(), []string{"Alloc", "Frees"}, gomock.Any()).Return(nil)
+		audit.EXPECT().LogMetrics(gomock.Any(), []string{"PollCount"}, gomock.Any()).Return(nil)
+
+		client := newClient(t, ms, audit)
+
+		stream, err := client.Push(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&pb.PushRequest{Metrics: []*pb.Metric{
+			{Id: "Alloc", Type: pb.MetricType_METRIC_TYPE_GAUGE, Value: pkg.Ptr(1.5)},
+			{Id: "Frees", Type: pb.MetricType_METRIC_TYPE_GAUGE, Value: pkg.Ptr(2.5)},
+		}}))
+		require.NoError(t, stream.Send(&pb.PushRequest{Metrics: []*pb.Metric{
+			{Id: "PollCount", Type: pb.MetricType_METRIC_TYPE_COUNTER, Delta: pkg.Ptr(int64(3))},
+		}}))
+
+		resp, err := stream.CloseAndRecv()
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), resp.GetReceived())
+	})
+
+	t.Run("Invalid batch aborts the stream", func(t *testing.T) {
+		t.Parallel()
+
+		client := newClient(t, new(mocks.MockMetricService), nil)
+
+		stream, err := client.Push(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, stream.Send(&pb.PushRequest{Metrics: []*pb.Metric{
+			{Type: pb.MetricType_METRIC_TYPE_GAUGE, Value: pkg.Ptr(1.5)},
+		}}))
+
+		_, err = stream.CloseAndRecv()
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
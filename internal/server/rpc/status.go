@@ -0,0 +1,36 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errStatusCodes = map[error]codes.Code{
+	errs.ErrInvalidMetricType:   codes.InvalidArgument,
+	errs.ErrInvalidMetricValue:  codes.InvalidArgument,
+	errs.ErrInvalidLabels:       codes.InvalidArgument,
+	errs.ErrNoMetricID:          codes.InvalidArgument,
+	errs.ErrMetricNotFound:      codes.NotFound,
+	errs.ErrBucketsMismatch:     codes.FailedPrecondition,
+	errs.ErrDatabaseUnavailable: codes.Unavailable,
+}
+
+// statusError converts an error to a gRPC status keeping internal details out of the message.
+func statusError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for e, code := range errStatusCodes {
+		if errors.Is(err, e) {
+			log.Debug().Err(err).Msg("rpc error")
+			return status.Error(code, e.Error())
+		}
+	}
+	log.Error().Err(err).Msg("rpc error")
+	return status.Error(codes.Internal, "internal error")
+}
@@ -2,37 +2,81 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/yogenyslav/ya-metrics/internal/config"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"github.com/yogenyslav/ya-metrics/internal/server/alert"
 	"github.com/yogenyslav/ya-metrics/internal/server/audit"
 	"github.com/yogenyslav/ya-metrics/internal/server/handler"
 	"github.com/yogenyslav/ya-metrics/internal/server/middleware"
 	"github.com/yogenyslav/ya-metrics/internal/server/repository"
+	"github.com/yogenyslav/ya-metrics/internal/server/rpc"
 	"github.com/yogenyslav/ya-metrics/internal/server/service"
 	"github.com/yogenyslav/ya-metrics/pkg/database"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/pkg/secure"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
-// Server serves HTTP requests.
+// silenceRepo stores alert silences.
+type silenceRepo interface {
+	Create(ctx context.Context, silence model.Silence) error
+	List(ctx context.Context) ([]model.Silence, error)
+	Expire(ctx context.Context, id string, now time.Time) error
+}
+
+// Server serves HTTP and gRPC requests.
 type Server struct {
 	router         chi.Router
+	http           *http.Server
+	grpc           *grpc.Server
+	tls            *tls.Config
+	stopJobs       context.CancelFunc
+	jobs           sync.WaitGroup
 	cfg            *config.Config
 	pg             database.TxDB
 	dumper         middleware.Dumper
+	dumpRepos      []repository.Repo
 	dumpOnShutdown func()
 }
 
 // NewServer creates new HTTP server.
 func NewServer(cfg *config.Config, l *zerolog.Logger) (*Server, error) {
+	var decryptor middleware.Decryptor
+	if cfg.Server.CryptoKey != "" {
+		key, err := secure.LoadPrivateKey(cfg.Server.CryptoKey)
+		if err != nil {
+			return nil, errs.Wrap(err, "load crypto key")
+		}
+		decryptor = secure.NewDecryptor(key)
+	}
+
+	var tlsCfg *tls.Config
+	if cfg.TLS != nil && cfg.TLS.CertFile != "" {
+		var err error
+		tlsCfg, err = secure.ServerTLSConfig(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile)
+		if err != nil {
+			return nil, errs.Wrap(err, "create TLS config")
+		}
+	}
+
 	router := chi.NewRouter()
 	router.Use(
 		middleware.WithLogging(l),
+		middleware.WithClientCert(),
+		middleware.WithDecryption(decryptor),
 		middleware.WithCompression(middleware.GzipCompression),
 		middleware.WithSignature(cfg.Server.SecureKey),
 	)
@@ -40,6 +84,7 @@ func NewServer(cfg *config.Config, l *zerolog.Logger) (*Server, error) {
 	srv := &Server{
 		router: router,
 		cfg:    cfg,
+		tls:    tlsCfg,
 	}
 
 	switch {
@@ -61,73 +106,192 @@ func NewServer(cfg *config.Config, l *zerolog.Logger) (*Server, error) {
 	return srv, nil
 }
 
-// Start starts the HTTP server.
+// Start starts the HTTP server, background jobs run until ctx is done or the server is shut down.
 func (s *Server) Start(ctx context.Context) error {
+	ctx, s.stopJobs = context.WithCancel(ctx)
+
 	var (
-		gaugeRepo   service.GaugeRepo
-		counterRepo service.CounterRepo
-		err         error
+		gaugeRepo     service.GaugeRepo
+		counterRepo   service.CounterRepo
+		histogramRepo service.HistogramRepo
+		summaryRepo   service.SummaryRepo
+		silenceRepo   silenceRepo
+		err           error
 	)
 
 	if s.pg == nil {
-		gaugeRepo, counterRepo, err = s.initRepos(ctx)
+		gaugeRepo, counterRepo, histogramRepo, summaryRepo, silenceRepo, err = s.initRepos(ctx)
 		if err != nil {
 			return errs.Wrap(err, "init repositories")
 		}
 	} else {
 		gaugeRepo = repository.NewMetricPostgresRepo[float64](s.pg)
 		counterRepo = repository.NewMetricPostgresRepo[int64](s.pg)
+		histogramRepo = repository.NewHistogramPostgresRepo(s.pg)
+		summaryRepo = repository.NewSummaryPostgresRepo(s.pg)
+		silenceRepo = repository.NewSilencePostgresRepo(s.pg)
 	}
 	s.router.Mount("/debug", chimw.Profiler())
 
-	metricService := service.NewService(gaugeRepo, counterRepo, database.NewUnitOfWork(s.pg))
+	metricService := service.NewService(
+		gaugeRepo,
+		counterRepo,
+		histogramRepo,
+		summaryRepo,
+		database.NewUnitOfWork(s.pg),
+	)
+	if s.cfg.History.Enabled {
+		metricService.EnableHistory()
+	}
+	if s.cfg.Server.StaleAfter > 0 {
+		metricService.MarkStaleAfter(time.Second * time.Duration(s.cfg.Server.StaleAfter))
+	}
+	if s.cfg.History.Retention != nil {
+		var rollupRepo service.RollupRepo
+		if s.pg == nil {
+			rollupRepo = repository.NewRollupInMemRepo()
+		} else {
+			rollupRepo = repository.NewRollupPostgresRepo(s.pg)
+		}
+		metricService.EnableRetention(s.cfg.History.Retention, rollupRepo)
+		s.Retention(ctx, metricService, defaultTickerFactory)
+	}
 	audit := audit.New(s.cfg.Audit)
 
-	h := handler.NewHandler(metricService, s.pg, audit)
-	h.RegisterRoutes(s.router)
+	var rules []alert.Rule
+	if s.cfg.Alert.RulesFile != "" {
+		rules, err = alert.LoadRules(s.cfg.Alert.RulesFile)
+		if err != nil {
+			return errs.Wrap(err, "load alerting rules")
+		}
+	}
+	alerts := alert.New(
+		rules,
+		metricService,
+		silenceRepo,
+		alert.NewNotifier(s.cfg.Alert, s.cfg.Server.SecureKey, s.cfg.Retry),
+	)
+	s.Alerting(ctx, alerts, defaultTickerFactory)
+
+	h := handler.NewHandler(metricService, s.pg, audit, alerts)
+	h.RegisterRoutes(s.router, middleware.WithTrustedSubnet(s.cfg.Server.TrustedSubnet))
+
+	if s.cfg.Server.GRPCAddr != "" {
+		lis, err := net.Listen("tcp", s.cfg.Server.GRPCAddr)
+		if err != nil {
+			return errs.Wrap(err, "listen gRPC address")
+		}
+
+		updateMethods := []string{
+			pb.Metrics_Update_FullMethodName,
+			pb.Metrics_UpdateBatch_FullMethodName,
+			pb.Metrics_Push_FullMethodName,
+		}
+		unary := []grpc.UnaryServerInterceptor{
+			middleware.UnaryRecovery(),
+			middleware.UnaryTrustedSubnet(s.cfg.Server.TrustedSubnet, updateMethods...),
+			middleware.UnarySignature(s.cfg.Server.SecureKey),
+		}
+		stream := []grpc.StreamServerInterceptor{
+			middleware.StreamRecovery(),
+			middleware.StreamTrustedSubnet(s.cfg.Server.TrustedSubnet, updateMethods...),
+			middleware.StreamSignature(s.cfg.Server.SecureKey),
+		}
+		if s.dumpRepos != nil {
+			unary = append(unary,
+				middleware.UnaryFileDumper(s.dumper, s.cfg.Dump.StoreInterval, updateMethods, s.dumpRepos...))
+			stream = append(stream,
+				middleware.StreamFileDumper(s.dumper, s.cfg.Dump.StoreInterval, updateMethods, s.dumpRepos...))
+		}
+		opts := []grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(unary...),
+			grpc.ChainStreamInterceptor(stream...),
+		}
+		if s.tls != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(s.tls)))
+		}
+		s.grpc = grpc.NewServer(opts...)
+		rpc.NewServer(metricService, audit).Register(s.grpc)
+		go s.serveGRPC(lis)
+	}
 
+	s.http = &http.Server{
+		Addr:      s.cfg.Server.Addr,
+		Handler:   s.router,
+		TLSConfig: s.tls,
+	}
 	go s.listen()
 
 	return nil
 }
 
 func (s *Server) listen() {
-	if err := http.ListenAndServe(s.cfg.Server.Addr, s.router); err != nil {
+	var err error
+	if s.tls != nil {
+		err = s.http.ListenAndServeTLS("", "")
+	} else {
+		err = s.http.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Err(err).Msg("failed serving HTTP")
 	}
 }
 
-func (s *Server) initRepos(ctx context.Context) (service.GaugeRepo, service.CounterRepo, error) {
-	var (
-		gaugeMetrics   repository.StorageState[float64]
-		counterMetrics repository.StorageState[int64]
-		err            error
-	)
+func (s *Server) serveGRPC(lis net.Listener) {
+	if err := s.grpc.Serve(lis); err != nil {
+		log.Err(err).Msg("failed serving gRPC")
+	}
+}
 
+func (s *Server) initRepos(
+	ctx context.Context,
+) (service.GaugeRepo, service.CounterRepo, service.HistogramRepo, service.SummaryRepo, silenceRepo, error) {
+	state := &repository.RestoredState{}
 	if s.cfg.Dump.Restore {
-		gaugeMetrics, counterMetrics, err = repository.RestoreMetrics(s.cfg.Dump.FileStoragePath)
+		var err error
+		state, err = repository.RestoreMetrics(s.cfg.Dump.FileStoragePath)
 		if err != nil {
-			return nil, nil, errs.Wrap(err, "restore metrics")
+			return nil, nil, nil, nil, nil, errs.Wrap(err, "restore metrics")
 		}
 	}
 
-	gaugeRepo := repository.NewMetricInMemRepo(gaugeMetrics)
-	counterRepo := repository.NewMetricInMemRepo(counterMetrics)
+	gaugeRepo := repository.NewMetricInMemRepo(state.Gauges)
+	counterRepo := repository.NewMetricInMemRepo(state.Counters)
+	histogramRepo := repository.NewHistogramInMemRepo(state.Histograms)
+	summaryRepo := repository.NewSummaryInMemRepo(state.Summaries)
+	silenceRepo := repository.NewSilenceInMemRepo(state.Silences)
 
 	if s.dumper != nil {
 		dumpingGaugeRepo, ok := any(gaugeRepo).(repository.Repo)
 		if !ok {
-			return nil, nil, errors.New("gauge repo does not implement repository.Repo")
+			return nil, nil, nil, nil, nil, errors.New("gauge repo does not implement repository.Repo")
 		}
 
 		dumpingCounterRepo, ok := any(counterRepo).(repository.Repo)
 		if !ok {
-			return nil, nil, errors.New("counter repo does not implement repository.Repo")
+			return nil, nil, nil, nil, nil, errors.New("counter repo does not implement repository.Repo")
+		}
+
+		dumpingHistogramRepo, ok := any(histogramRepo).(repository.Repo)
+		if !ok {
+			return nil, nil, nil, nil, nil, errors.New("histogram repo does not implement repository.Repo")
+		}
+
+		dumpingSummaryRepo, ok := any(summaryRepo).(repository.Repo)
+		if !ok {
+			return nil, nil, nil, nil, nil, errors.New("summary repo does not implement repository.Repo")
 		}
 
-		s.Dumping(ctx, s.dumper, defaultTickerFactory, dumpingGaugeRepo, dumpingCounterRepo)
+		repos := []repository.Repo{
+			dumpingGaugeRepo,
+			dumpingCounterRepo,
+			dumpingHistogramRepo,
+			dumpingSummaryRepo,
+			silenceRepo,
+		}
+		s.Dumping(ctx, s.dumper, defaultTickerFactory, repos...)
 		s.dumpOnShutdown = func() {
-			err := s.dumper.Dump(context.Background(), gaugeRepo, counterRepo)
+			err := s.dumper.Dump(context.Background(), repos...)
 			if err != nil {
 				log.Warn().Err(err).Msg("failed to dump data on shutdown")
 			} else {
@@ -135,17 +299,59 @@ func (s *Server) initRepos(ctx context.Context) (service.GaugeRepo, service.Coun
 			}
 		}
 		s.router.Use(
-			middleware.WithFileDumper(s.dumper, s.cfg.Dump.StoreInterval, dumpingGaugeRepo, dumpingCounterRepo),
+			middleware.WithFileDumper(s.dumper, s.cfg.Dump.StoreInterval, repos...),
 		)
+		s.dumpRepos = repos
 	}
 
-	return gaugeRepo, counterRepo, nil
+	return gaugeRepo, counterRepo, histogramRepo, summaryRepo, silenceRepo, nil
 }
 
-// Shutdown performs server shutdown.
-func (s *Server) Shutdown() {
+// Shutdown stops accepting connections and waits for in-flight requests until ctx is done,
+// then stops background jobs, dumps the data for the last time and closes the database.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var errList []error
+
+	if s.http != nil {
+		if err := s.http.Shutdown(ctx); err != nil {
+			errList = append(errList, errs.Wrap(err, "shutdown HTTP server"))
+			s.http.Close()
+		}
+	}
+	if s.grpc != nil {
+		if err := s.stopGRPC(ctx); err != nil {
+			errList = append(errList, errs.Wrap(err, "shutdown gRPC server"))
+		}
+	}
+
+	if s.stopJobs != nil {
+		s.stopJobs()
+	}
+	s.jobs.Wait()
+
+	if s.dumpOnShutdown != nil {
+		s.dumpOnShutdown()
+	}
 	if s.pg != nil {
 		s.pg.Close()
 	}
-	s.dumpOnShutdown()
+
+	return errors.Join(errList...)
+}
+
+// stopGRPC waits for RPCs to finish until ctx is done and cancels the rest.
+func (s *Server) stopGRPC(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
 }
@@ -2,26 +2,114 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 )
 
-// GetMetric retrieves a metric by its type and name.
-func (s *Service) GetMetric(ctx context.Context, metricType, metricID string) (*model.MetricsDto, error) {
+// GetMetric retrieves a metric series by its type, name and labels.
+//
+// A lookup without labels falls back to the only series with the name, so that clients unaware of labels
+// still read metrics labeled by the agent instance. Names with several series are not resolved.
+func (s *Service) GetMetric(
+	ctx context.Context,
+	metricType, metricID string,
+	labels model.Labels,
+) (*model.MetricsDto, error) {
+	m, err := s.getMetric(ctx, metricType, metricID, labels)
+	if err == nil || len(labels) > 0 || errors.Is(err, errs.ErrInvalidMetricType) {
+		return m, err
+	}
+
+	series, listErr := s.seriesLabels(ctx, metricType, metricID)
+	if listErr != nil || len(series) != 1 {
+		return nil, err
+	}
+	return s.getMetric(ctx, metricType, metricID, series[0])
+}
+
+// seriesLabels returns labels of every series with the type and name.
+func (s *Service) seriesLabels(ctx context.Context, metricType, metricID string) ([]model.Labels, error) {
+	var labels []model.Labels
+
 	switch metricType {
 	case model.Gauge:
-		gauge, err := s.gr.Get(ctx, metricID, metricType)
+		gauges, err := s.gr.List(ctx)
+		if err != nil {
+			return nil, errs.Wrap(err, "list gauge metrics")
+		}
+		for _, g := range gauges {
+			if g.ID == metricID && g.Type == metricType {
+				labels = append(labels, g.Labels)
+			}
+		}
+	case model.Counter:
+		counters, err := s.cr.List(ctx)
+		if err != nil {
+			return nil, errs.Wrap(err, "list counter metrics")
+		}
+		for _, c := range counters {
+			if c.ID == metricID && c.Type == metricType {
+				labels = append(labels, c.Labels)
+			}
+		}
+	case model.Histogram:
+		histograms, err := s.hr.List(ctx)
+		if err != nil {
+			return nil, errs.Wrap(err, "list histogram metrics")
+		}
+		for _, h := range histograms {
+			if h.ID == metricID {
+				labels = append(labels, h.Labels)
+			}
+		}
+	case model.Summary:
+		summaries, err := s.sr.List(ctx)
+		if err != nil {
+			return nil, errs.Wrap(err, "list summary metrics")
+		}
+		for _, sm := range summaries {
+			if sm.ID == metricID {
+				labels = append(labels, sm.Labels)
+			}
+		}
+	}
+
+	return labels, nil
+}
+
+func (s *Service) getMetric(
+	ctx context.Context,
+	metricType, metricID string,
+	labels model.Labels,
+) (*model.MetricsDto, error) {
+	switch metricType {
+	case model.Gauge:
+		gauge, err := s.gr.Get(ctx, metricID, metricType, labels)
 		if err != nil {
 			return nil, errs.Wrap(err)
 		}
-		return gauge.ToDto(), nil
+		return s.markStale(gauge.ToDto(), time.Now()), nil
 	case model.Counter:
-		counter, err := s.cr.Get(ctx, metricID, metricType)
+		counter, err := s.cr.Get(ctx, metricID, metricType, labels)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		return s.markStale(counter.ToDto(), time.Now()), nil
+	case model.Histogram:
+		histogram, err := s.hr.Get(ctx, metricID, labels)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		return s.markStale(histogram.ToDto(), time.Now()), nil
+	case model.Summary:
+		summary, err := s.sr.Get(ctx, metricID, labels)
 		if err != nil {
 			return nil, errs.Wrap(err)
 		}
-		return counter.ToDto(), nil
+		return s.markStale(summary.ToDto(), time.Now()), nil
 	default:
 		return nil, errs.Wrap(errs.ErrInvalidMetricType, metricType)
 	}
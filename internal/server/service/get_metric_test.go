@@ -70,29 +70,31 @@ func TestService_GetMetric(t *testing.T) {
 			if tt.wantMetric != nil {
 				switch tt.metricType {
 				case model.Gauge:
-					gr.On("Get", mock.Anything, tt.metricID, model.Gauge).Return(
+					gr.On("Get", mock.Anything, tt.metricID, model.Gauge, model.Labels(nil)).Return(
 						model.NewGaugeMetric(tt.metricID), nil,
 					)
 				case model.Counter:
-					cr.On("Get", mock.Anything, tt.metricID, model.Counter).Return(
+					cr.On("Get", mock.Anything, tt.metricID, model.Counter, model.Labels(nil)).Return(
 						model.NewCounterMetric(tt.metricID), nil,
 					)
 				}
 			} else {
 				switch tt.metricType {
 				case model.Gauge:
-					gr.On("Get", mock.Anything, tt.metricID, model.Gauge).Return(
+					gr.On("Get", mock.Anything, tt.metricID, model.Gauge, model.Labels(nil)).Return(
 						&model.Metrics[float64]{}, errs.ErrMetricNotFound,
 					)
+					gr.On("List", mock.Anything).Return([]model.Metrics[float64]{}, nil)
 				case model.Counter:
-					cr.On("Get", mock.Anything, tt.metricID, model.Counter).Return(
+					cr.On("Get", mock.Anything, tt.metricID, model.Counter, model.Labels(nil)).Return(
 						&model.Metrics[int64]{}, errs.ErrMetricNotFound,
 					)
+					cr.On("List", mock.Anything).Return([]model.Metrics[int64]{}, nil)
 				}
 			}
 
-			s := NewService(gr, cr, nil)
-			metric, err := s.GetMetric(context.Background(), tt.metricType, tt.metricID)
+			s := NewService(gr, cr, nil, nil, nil)
+			metric, err := s.GetMetric(context.Background(), tt.metricType, tt.metricID, nil)
 			if tt.wantErr {
 				require.Error(t, err)
 			} else {
@@ -102,3 +104,53 @@ func TestService_GetMetric(t *testing.T) {
 		})
 	}
 }
+
+func TestService_GetMetric_withoutLabels(t *testing.T) {
+	t.Parallel()
+
+	host := model.Labels{model.InstanceLabel: "host-a"}
+	tests := []struct {
+		name    string
+		series  []model.Metrics[float64]
+		want    *model.MetricsDto
+		wantErr bool
+	}{
+		{
+			name:   "Single labeled series",
+			series: []model.Metrics[float64]{{ID: "Alloc", Type: model.Gauge, Value: 1, Labels: host}},
+			want:   &model.MetricsDto{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.0), Labels: host},
+		},
+		{
+			name: "Several labeled series",
+			series: []model.Metrics[float64]{
+				{ID: "Alloc", Type: model.Gauge, Value: 1, Labels: host},
+				{ID: "Alloc", Type: model.Gauge, Value: 2, Labels: model.Labels{model.InstanceLabel: "host-b"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gr := &mocks.MockGaugeRepo{}
+			gr.On("Get", mock.Anything, "Alloc", model.Gauge, model.Labels(nil)).Return(
+				&model.Metrics[float64]{}, errs.ErrMetricNotFound,
+			)
+			gr.On("List", mock.Anything).Return(tt.series, nil)
+			if !tt.wantErr {
+				gr.On("Get", mock.Anything, "Alloc", model.Gauge, host).Return(&tt.series[0], nil)
+			}
+
+			s := NewService(gr, nil, nil, nil, nil)
+			got, err := s.GetMetric(context.Background(), model.Gauge, "Alloc", nil)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errs.ErrMetricNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// GetMetricHistory retrieves points of a metric series recorded between from and to inclusive.
+func (s *Service) GetMetricHistory(
+	ctx context.Context,
+	metricType, metricID string,
+	labels model.Labels,
+	from, to time.Time,
+) ([]*model.Point, error) {
+	if !s.history {
+		return nil, errs.Wrap(errs.ErrHistoryDisabled)
+	}
+	if to.Before(from) {
+		return nil, errs.Wrap(errs.ErrInvalidTimeRange, "end is before start")
+	}
+
+	switch metricType {
+	case model.Gauge:
+		samples, err := s.gr.Range(ctx, metricID, metricType, labels, from, to)
+		if err != nil {
+			return nil, errs.Wrap(err, "range gauge samples")
+		}
+		return toPoints(samples), nil
+	case model.Counter:
+		samples, err := s.cr.Range(ctx, metricID, metricType, labels, from, to)
+		if err != nil {
+			return nil, errs.Wrap(err, "range counter samples")
+		}
+		return toPoints(samples), nil
+	default:
+		return nil, errs.Wrap(errs.ErrInvalidMetricType, metricType)
+	}
+}
+
+func (s *Service) recordGauge(ctx context.Context, m *model.Metrics[float64], ts time.Time) error {
+	if !s.history {
+		return nil
+	}
+	return errs.Wrap(s.gr.Append(ctx, model.NewSample(m, ts)), "append gauge sample")
+}
+
+// recordCounter stores the total returned by the update, so the history reflects what GetMetric returned at ts.
+func (s *Service) recordCounter(ctx context.Context, m *model.Metrics[int64], total int64, ts time.Time) error {
+	if !s.history {
+		return nil
+	}
+
+	sample := model.NewSample(m, ts)
+	sample.Value = total
+	return errs.Wrap(s.cr.Append(ctx, sample), "append counter sample")
+}
+
+func toPoints[T int64 | float64](samples []model.Sample[T]) []*model.Point {
+	points := make([]*model.Point, 0, len(samples))
+	for _, sample := range samples {
+		points = append(points, sample.ToPoint())
+	}
+	return points
+}
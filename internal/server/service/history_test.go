@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/server/repository"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestService_GetMetricHistory(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	to := from.Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		history    bool
+		metricType string
+		from       time.Time
+		to         time.Time
+		setup      func(gr *mocks.MockGaugeRepo, cr *mocks.MockCounterRepo)
+		want       []*model.Point
+		wantErr    error
+	}{
+		{
+			name:       "Gauge history",
+			history:    true,
+			metricType: model.Gauge,
+			from:       from,
+			to:         to,
+			setup: func(gr *mocks.MockGaugeRepo, _ *mocks.MockCounterRepo) {
+				gr.On("Range", mock.Anything, "metric1", model.Gauge, model.Labels(nil), from, to).Return([]model.Sample[float64]{
+					{ID: "metric1", Type: model.Gauge, Value: 1.5, Timestamp: from},
+					{ID: "metric1", Type: model.Gauge, Value: 2.5, Timestamp: to},
+				}, nil)
+			},
+			want: []*model.Point{
+				{Timestamp: from.Unix(), Value: pkg.Ptr(1.5)},
+				{Timestamp: to.Unix(), Value: pkg.Ptr(2.5)},
+			},
+		},
+		{
+			name:       "Counter history",
+			history:    true,
+			metricType: model.Counter,
+			from:       from,
+			to:         to,
+			setup: func(_ *mocks.MockGaugeRepo, cr *mocks.MockCounterRepo) {
+				cr.On("Range", mock.Anything, "metric1", model.Counter, model.Labels(nil), from, to).Return([]model.Sample[int64]{
+					{ID: "metric1", Type: model.Counter, Value: 3, Timestamp: from},
+				}, nil)
+			},
+			want: []*model.Point{
+				{Timestamp: from.Unix(), Delta: pkg.Ptr[int64](3)},
+			},
+		},
+		{
+			name:       "History disabled",
+			history:    false,
+			metricType: model.Gauge,
+			from:       from,
+			to:         to,
+			wantErr:    errs.ErrHistoryDisabled,
+		},
+		{
+			name:       "End before start",
+			history:    true,
+			metricType: model.Gauge,
+			from:       to,
+			to:         from,
+			wantErr:    errs.ErrInvalidTimeRange,
+		},
+		{
+			name:       "Invalid metric type",
+			history:    true,
+			metricType: "invalid_type",
+			from:       from,
+			to:         to,
+			wantErr:    errs.ErrInvalidMetricType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gr := &mocks.MockGaugeRepo{}
+			cr := &mocks.MockCounterRepo{}
+			if tt.setup != nil {
+				tt.setup(gr, cr)
+			}
+
+			s := NewService(gr, cr, nil, nil, nil)
+			if tt.history {
+				s.EnableHistory()
+			}
+
+			got, err := s.GetMetricHistory(context.Background(), tt.metricType, "metric1", nil, tt.from, tt.to)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestService_UpdateMetric_history(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	gr := &mocks.MockGaugeRepo{}
+	cr := &mocks.MockCounterRepo{}
+
+	s := NewService(gr, cr, nil, nil, nil)
+	s.EnableHistory()
+
+	cr.On("Update", mock.Anything, mock.Anything).Return(int64(15), nil)
+	cr.On("Append", mock.Anything, mock.MatchedBy(func(s *model.Sample[int64]) bool {
+		return s.ID == "counter_metric" && s.Value == 15 && !s.Timestamp.IsZero()
+	})).Return(nil)
+
+	err := s.UpdateMetric(ctx, &model.MetricsDto{
+		ID:    "counter_metric",
+		Type:  model.Counter,
+		Delta: pkg.Ptr[int64](5),
+	})
+	require.NoError(t, err)
+	cr.AssertNumberOfCalls(t, "Append", 1)
+}
+
+func TestService_UpdateMetric_concurrentCounterHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cr := repository.NewMetricInMemRepo[int64](nil)
+	s := NewService(repository.NewMetricInMemRepo[float64](nil), cr, nil, nil, nil)
+	s.EnableHistory()
+
+	const updates = 50
+	var wg sync.WaitGroup
+	wg.Add(updates)
+	for range updates {
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.UpdateMetric(ctx, &model.MetricsDto{
+				ID:    "counter_metric",
+				Type:  model.Counter,
+				Delta: pkg.Ptr[int64](1),
+			}))
+		}()
+	}
+	wg.Wait()
+
+	samples, err := cr.Range(ctx, "counter_metric", model.Counter, nil, time.Time{}, time.Now())
+	require.NoError(t, err)
+
+	totals := make([]int64, 0, len(samples))
+	for _, sample := range samples {
+		totals = append(totals, sample.Value)
+	}
+	slices.Sort(totals)
+	require.NotEmpty(t, totals)
+	assert.Len(t, slices.Compact(slices.Clone(totals)), len(totals), "every update records its own total")
+	assert.LessOrEqual(t, totals[len(totals)-1], int64(updates))
+}
@@ -1,13 +1,17 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
 )
 
-// ListMetrics retrieves all gauge and counter metrics.
+// ListMetrics retrieves metrics of all types.
 func (s *Service) ListMetrics(ctx context.Context) ([]*model.MetricsDto, error) {
 	gauges, err := s.gr.List(ctx)
 	if err != nil {
@@ -17,15 +21,69 @@ func (s *Service) ListMetrics(ctx context.Context) ([]*model.MetricsDto, error)
 	if err != nil {
 		return nil, errs.Wrap(err, "list counter metrics")
 	}
+	histograms, err := s.hr.List(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err, "list histogram metrics")
+	}
+	summaries, err := s.sr.List(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err, "list summary metrics")
+	}
 
-	result := make([]*model.MetricsDto, 0, len(gauges)+len(counters))
+	result := make([]*model.MetricsDto, 0, len(gauges)+len(counters)+len(histograms)+len(summaries))
 
+	now := time.Now()
 	for _, g := range gauges {
-		result = append(result, g.ToDto())
+		result = append(result, s.markStale(g.ToDto(), now))
 	}
 	for _, c := range counters {
-		result = append(result, c.ToDto())
+		result = append(result, s.markStale(c.ToDto(), now))
+	}
+	for _, h := range histograms {
+		result = append(result, s.markStale(h.ToDto(), now))
+	}
+	for _, sm := range summaries {
+		result = append(result, s.markStale(sm.ToDto(), now))
 	}
 
 	return result, nil
 }
+
+// ListMetricGroups retrieves all metric series grouped by metric type and name.
+//
+// Groups are ordered by name and type, series within a group are ordered by labels.
+func (s *Service) ListMetricGroups(ctx context.Context) ([]*model.MetricGroup, error) {
+	metrics, err := s.ListMetrics(ctx)
+	if err != nil {
+		return nil, errs.Wrap(err, "list metrics")
+	}
+
+	type groupKey struct {
+		id    string
+		mtype string
+	}
+
+	index := make(map[groupKey]*model.MetricGroup)
+	groups := make([]*model.MetricGroup, 0)
+	for _, m := range metrics {
+		key := groupKey{id: m.ID, mtype: m.Type}
+		group, ok := index[key]
+		if !ok {
+			group = &model.MetricGroup{ID: m.ID, Type: m.Type}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		group.Series = append(group.Series, m)
+	}
+
+	slices.SortFunc(groups, func(a, b *model.MetricGroup) int {
+		return cmp.Or(strings.Compare(a.ID, b.ID), strings.Compare(a.Type, b.Type))
+	})
+	for _, group := range groups {
+		slices.SortFunc(group.Series, func(a, b *model.MetricsDto) int {
+			return strings.Compare(a.Labels.Key(), b.Labels.Key())
+		})
+	}
+
+	return groups, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg"
 	"github.com/yogenyslav/ya-metrics/tests/mocks"
@@ -17,10 +18,12 @@ func TestService_ListMetrics(t *testing.T) {
 	ctx := context.Background()
 
 	tests := []struct {
-		name           string
-		gaugeMetrics   []model.Metrics[float64]
-		counterMetrics []model.Metrics[int64]
-		want           []*model.MetricsDto
+		name             string
+		gaugeMetrics     []model.Metrics[float64]
+		counterMetrics   []model.Metrics[int64]
+		histogramMetrics []model.HistogramMetric
+		summaryMetrics   []model.SummaryMetric
+		want             []*model.MetricsDto
 	}{
 		{
 			name: "List all metrics",
@@ -32,18 +35,53 @@ func TestService_ListMetrics(t *testing.T) {
 				*model.NewCounterMetric("request_count"),
 				*model.NewCounterMetric("error_count"),
 			},
+			histogramMetrics: []model.HistogramMetric{
+				{
+					ID: "latency",
+					HistogramData: model.HistogramData{
+						Bounds: []float64{0.1, 1},
+						Counts: []int64{1, 2, 0},
+						Count:  3,
+						Sum:    1.5,
+					},
+				},
+			},
+			summaryMetrics: []model.SummaryMetric{
+				*model.NewSummaryMetric("request_duration", nil, 2),
+			},
 			want: []*model.MetricsDto{
 				{ID: "mem_alloc", Type: model.Gauge, Value: pkg.Ptr(0.0)},
 				{ID: "cpu_usage", Type: model.Gauge, Value: pkg.Ptr(0.0)},
 				{ID: "request_count", Type: model.Counter, Delta: pkg.Ptr[int64](0)},
 				{ID: "error_count", Type: model.Counter, Delta: pkg.Ptr[int64](0)},
+				{
+					ID:   "latency",
+					Type: model.Histogram,
+					Histogram: &model.HistogramData{
+						Bounds: []float64{0.1, 1},
+						Counts: []int64{1, 2, 0},
+						Count:  3,
+						Sum:    1.5,
+					},
+				},
+				{
+					ID:   "request_duration",
+					Type: model.Summary,
+					Summary: &model.SummaryData{
+						Count:     1,
+						Sum:       2,
+						Quantiles: map[string]float64{"0.5": 2, "0.9": 2, "0.99": 2},
+					},
+				},
 			},
 		},
 		{
-			name:           "List with no metrics",
-			gaugeMetrics:   []model.Metrics[float64]{},
-			counterMetrics: []model.Metrics[int64]{},
-			want:           []*model.MetricsDto{},
+			name:             "List with no metrics",
+			gaugeMetrics:     []model.Metrics[float64]{},
+			counterMetrics:   []model.Metrics[int64]{},
+			histogramMetrics: []model.HistogramMetric{},
+			summaryMetrics:   []model.SummaryMetric{},
+			want:             []*model.MetricsDto{},
 		},
 	}
 
@@ -53,14 +91,68 @@ func TestService_ListMetrics(t *testing.T) {
 
 			gr := &mocks.MockGaugeRepo{}
 			cr := &mocks.MockCounterRepo{}
+			hr := &mocks.MockHistogramRepo{}
+			sr := &mocks.MockSummaryRepo{}
 
 			gr.On("List", mock.Anything).Return(tt.gaugeMetrics, nil)
 			cr.On("List", mock.Anything).Return(tt.counterMetrics, nil)
+			hr.On("List", mock.Anything).Return(tt.histogramMetrics, nil)
+			sr.On("List", mock.Anything).Return(tt.summaryMetrics, nil)
 
-			s := NewService(gr, cr, nil)
+			s := NewService(gr, cr, hr, sr, nil)
 			metrics, err := s.ListMetrics(ctx)
 			assert.NoError(t, err)
 			assert.ElementsMatch(t, tt.want, metrics)
 		})
 	}
 }
+
+func TestService_ListMetricGroups(t *testing.T) {
+	t.Parallel()
+
+	gr := &mocks.MockGaugeRepo{}
+	cr := &mocks.MockCounterRepo{}
+
+	gr.On("List", mock.Anything).Return([]model.Metrics[float64]{
+		{ID: "Alloc", Type: model.Gauge, Value: 2, Labels: model.Labels{model.InstanceLabel: "b"}},
+		{ID: "Alloc", Type: model.Gauge, Value: 1, Labels: model.Labels{model.InstanceLabel: "a"}},
+	}, nil)
+	cr.On("List", mock.Anything).Return([]model.Metrics[int64]{
+		{ID: "PollCount", Type: model.Counter, Value: 5, Labels: model.Labels{model.InstanceLabel: "a"}},
+		{ID: "Alloc", Type: model.Counter, Value: 3},
+	}, nil)
+	hr := &mocks.MockHistogramRepo{}
+	hr.On("List", mock.Anything).Return([]model.HistogramMetric{}, nil)
+	sr := &mocks.MockSummaryRepo{}
+	sr.On("List", mock.Anything).Return([]model.SummaryMetric{}, nil)
+
+	s := NewService(gr, cr, hr, sr, nil)
+	groups, err := s.ListMetricGroups(context.Background())
+	require.NoError(t, err)
+
+	want := []*model.MetricGroup{
+		{
+			ID:   "Alloc",
+			Type: model.Counter,
+			Series: []*model.MetricsDto{
+				{ID: "Alloc", Type: model.Counter, Delta: pkg.Ptr[int64](3)},
+			},
+		},
+		{
+			ID:   "Alloc",
+			Type: model.Gauge,
+			Series: []*model.MetricsDto{
+				{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(1.0), Labels: model.Labels{model.InstanceLabel: "a"}},
+				{ID: "Alloc", Type: model.Gauge, Value: pkg.Ptr(2.0), Labels: model.Labels{model.InstanceLabel: "b"}},
+			},
+		},
+		{
+			ID:   "PollCount",
+			Type: model.Counter,
+			Series: []*model.MetricsDto{
+				{ID: "PollCount", Type: model.Counter, Delta: pkg.Ptr[int64](5), Labels: model.Labels{model.InstanceLabel: "a"}},
+			},
+		},
+	}
+	assert.Equal(t, want, groups)
+}
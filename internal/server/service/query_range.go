@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+const (
+	maxRangePoints = 11000
+	// maxStepSeconds bounds step and window, so that they convert to a duration without overflow.
+	maxStepSeconds = 365 * 24 * 60 * 60
+)
+
+// QueryRange returns metric values sampled at every step between start and end.
+//
+// The value at a step is the latest sample recorded within one step before it,
+// steps without such sample are omitted.
+// A query without labels falls back to the only series of the metric, as GetMetric does.
+func (s *Service) QueryRange(ctx context.Context, q *model.RangeQuery) (*model.Series, error) {
+	if !s.history {
+		return nil, errs.Wrap(errs.ErrHistoryDisabled)
+	}
+	if q.Step <= 0 {
+		return nil, errs.Wrap(errs.ErrInvalidTimeRange, "step must be positive")
+	}
+	if q.Step > maxStepSeconds {
+		return nil, errs.Wrap(errs.ErrInvalidTimeRange, "step is too large")
+	}
+	if q.End < q.Start {
+		return nil, errs.Wrap(errs.ErrInvalidTimeRange, "end is before start")
+	}
+	if (q.End-q.Start)/q.Step >= maxRangePoints {
+		return nil, errs.Wrap(errs.ErrInvalidTimeRange, "too many points requested")
+	}
+
+	start := time.Unix(q.Start, 0)
+	end := time.Unix(q.End, 0)
+	step := time.Second * time.Duration(q.Step)
+
+	series, err := s.queryRange(ctx, q, start, end, step)
+	if err != nil || len(series.Points) > 0 || len(q.Labels) > 0 {
+		return series, err
+	}
+
+	labels, listErr := s.seriesLabels(ctx, q.Type, q.ID)
+	if listErr != nil || len(labels) != 1 || len(labels[0]) == 0 {
+		return series, nil
+	}
+	resolved := *q
+	resolved.Labels = labels[0]
+	return s.queryRange(ctx, &resolved, start, end, step)
+}
+
+// queryRange samples the series selected by the exact labels of the query.
+func (s *Service) queryRange(
+	ctx context.Context,
+	q *model.RangeQuery,
+	start, end time.Time,
+	step time.Duration,
+) (*model.Series, error) {
+	series := &model.Series{
+		ID:     q.ID,
+		Type:   q.Type,
+		Labels: q.Labels,
+	}
+
+	if q.Func != "" {
+		return s.queryCounterFunc(ctx, q, series, start, end, step)
+	}
+
+	if tier, ok := s.rollupTier(start.Add(-step)); ok {
+		return s.queryRollups(ctx, q, series, tier, start, end, step)
+	}
+
+	switch q.Type {
+	case model.Gauge:
+		samples, err := s.gr.Range(ctx, q.ID, q.Type, q.Labels, start.Add(-step), end)
+		if err != nil {
+			return nil, errs.Wrap(err, "range gauge samples")
+		}
+		series.Points = sampleAtStep(samples, start, end, step, step)
+	case model.Counter:
+		samples, err := s.cr.Range(ctx, q.ID, q.Type, q.Labels, start.Add(-step), end)
+		if err != nil {
+			return nil, errs.Wrap(err, "range counter samples")
+		}
+		series.Points = sampleAtStep(samples, start, end, step, step)
+	default:
+		return nil, errs.Wrap(errs.ErrInvalidMetricType, q.Type)
+	}
+
+	return series, nil
+}
+
+// queryRollups serves the query from the rollups of the tier, when raw samples for its start are already removed.
+//
+// A rollup is looked up within its resolution if it exceeds the step.
+func (s *Service) queryRollups(
+	ctx context.Context,
+	q *model.RangeQuery,
+	series *model.Series,
+	tier model.RollupTier,
+	start, end time.Time,
+	step time.Duration,
+) (*model.Series, error) {
+	if q.Type != model.Gauge && q.Type != model.Counter {
+		return nil, errs.Wrap(errs.ErrInvalidMetricType, q.Type)
+	}
+
+	lookback := max(step, tier.Resolution)
+	from := start.Add(-lookback - tier.Resolution)
+	rollups, err := s.rr.Range(ctx, q.ID, q.Type, q.Labels, resolutionSeconds(tier), from, end)
+	if err != nil {
+		return nil, errs.Wrap(err, "range rollups")
+	}
+
+	if q.Type == model.Gauge {
+		series.Points = sampleAtStep(rollupSamples[float64](rollups), start, end, step, lookback)
+	} else {
+		series.Points = sampleAtStep(rollupSamples[int64](rollups), start, end, step, lookback)
+	}
+	series.Resolution = resolutionSeconds(tier)
+
+	return series, nil
+}
+
+// sampleAtStep expects samples ordered by timestamp.
+//
+// The value at a step is the latest sample within lookback before it.
+func sampleAtStep[T int64 | float64](
+	samples []model.Sample[T],
+	start, end time.Time,
+	step, lookback time.Duration,
+) []*model.Point {
+	points := make([]*model.Point, 0, int(end.Sub(start)/step)+1)
+
+	var (
+		last *model.Sample[T]
+		i    int
+	)
+	for ts := start; !ts.After(end); ts = ts.Add(step) {
+		for i < len(samples) && !samples[i].Timestamp.After(ts) {
+			last = &samples[i]
+			i++
+		}
+
+		if last == nil || !last.Timestamp.After(ts.Add(-lookback)) {
+			continue
+		}
+
+		point := last.ToPoint()
+		point.Timestamp = ts.Unix()
+		points = append(points, point)
+	}
+
+	return points
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestService_QueryRange(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	sample := func(offsetSec int64, value float64) model.Sample[float64] {
+		return model.Sample[float64]{
+			ID:        "metric1",
+			Type:      model.Gauge,
+			Value:     value,
+			Timestamp: start.Add(time.Duration(offsetSec) * time.Second),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		query   *model.RangeQuery
+		samples []model.Sample[float64]
+		want    []*model.Point
+		wantErr error
+	}{
+		{
+			name:  "Latest sample within step is used",
+			query: &model.RangeQuery{ID: "metric1", Type: model.Gauge, Start: 1000, End: 1040, Step: 10},
+			samples: []model.Sample[float64]{
+				sample(-5, 1),
+				sample(3, 2),
+				sample(7, 3),
+				sample(35, 4),
+			},
+			want: []*model.Point{
+				{Timestamp: 1000, Value: pkg.Ptr(1.0)},
+				{Timestamp: 1010, Value: pkg.Ptr(3.0)},
+				{Timestamp: 1040, Value: pkg.Ptr(4.0)},
+			},
+		},
+		{
+			name:    "No samples",
+			query:   &model.RangeQuery{ID: "metric1", Type: model.Gauge, Start: 1000, End: 1040, Step: 10},
+			samples: []model.Sample[float64]{},
+			want:    []*model.Point{},
+		},
+		{
+			name:    "Zero step",
+			query:   &model.RangeQuery{ID: "metric1", Type: model.Gauge, Start: 1000, End: 1040},
+			wantErr: errs.ErrInvalidTimeRange,
+		},
+		{
+			name:    "Too many points",
+			query:   &model.RangeQuery{ID: "metric1", Type: model.Gauge, Start: 0, End: maxRangePoints, Step: 1},
+			wantErr: errs.ErrInvalidTimeRange,
+		},
+		{
+			name:    "Step overflowing duration",
+			query:   &model.RangeQuery{ID: "metric1", Type: model.Gauge, Start: 1000, End: 1040, Step: 1e10},
+			wantErr: errs.ErrInvalidTimeRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gr := &mocks.MockGaugeRepo{}
+			cr := &mocks.MockCounterRepo{}
+			if tt.samples != nil {
+				gr.On("Range", mock.Anything, "metric1", model.Gauge, model.Labels(nil), mock.Anything, mock.Anything).
+					Return(tt.samples, nil)
+			}
+			if tt.samples != nil && len(tt.want) == 0 {
+				gr.On("List", mock.Anything).Return([]model.Metrics[float64]{}, nil)
+			}
+
+			s := NewService(gr, cr, nil, nil, nil)
+			s.EnableHistory()
+
+			got, err := s.QueryRange(context.Background(), tt.query)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Points)
+		})
+	}
+}
+
+func TestService_QueryRange_onlySeries(t *testing.T) {
+	t.Parallel()
+
+	labels := model.Labels{model.InstanceLabel: "agent-1"}
+	gr := &mocks.MockGaugeRepo{}
+	gr.On("Range", mock.Anything, "Alloc", model.Gauge, model.Labels(nil), mock.Anything, mock.Anything).
+		Return([]model.Sample[float64]{}, nil)
+	gr.On("List", mock.Anything).
+		Return([]model.Metrics[float64]{{ID: "Alloc", Type: model.Gauge, Labels: labels}}, nil)
+	gr.On("Range", mock.Anything, "Alloc", model.Gauge, labels, mock.Anything, mock.Anything).
+		Return([]model.Sample[float64]{
+			{ID: "Alloc", Type: model.Gauge, Labels: labels, Value: 12.5, Timestamp: time.Unix(995, 0)},
+		}, nil)
+
+	s := NewService(gr, nil, nil, nil, nil)
+	s.EnableHistory()
+
+	got, err := s.QueryRange(context.Background(), &model.RangeQuery{
+		ID:    "Alloc",
+		Type:  model.Gauge,
+		Start: 1000,
+		End:   1000,
+		Step:  10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, labels, got.Labels)
+	assert.Equal(t, []*model.Point{{Timestamp: 1000, Value: pkg.Ptr(12.5)}}, got.Points)
+}
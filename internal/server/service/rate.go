@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// queryCounterFunc computes the rate or the increase of a counter over the window preceding every step.
+func (s *Service) queryCounterFunc(
+	ctx context.Context,
+	q *model.RangeQuery,
+	series *model.Series,
+	start, end time.Time,
+	step time.Duration,
+) (*model.Series, error) {
+	if q.Func != model.FuncRate && q.Func != model.FuncIncrease {
+		return nil, errs.Wrap(errs.ErrInvalidQueryFunc, q.Func)
+	}
+	if q.Type != model.Counter {
+		return nil, errs.Wrap(errs.ErrInvalidMetricType, q.Func+" is only defined for counters")
+	}
+	if q.Window < 0 {
+		return nil, errs.Wrap(errs.ErrInvalidTimeRange, "window must be positive")
+	}
+	if q.Window > maxStepSeconds {
+		return nil, errs.Wrap(errs.ErrInvalidTimeRange, "window is too large")
+	}
+
+	window := step
+	if q.Window > 0 {
+		window = time.Second * time.Duration(q.Window)
+	}
+
+	var samples []model.Sample[int64]
+	if tier, ok := s.rollupTier(start.Add(-window)); ok {
+		from := start.Add(-window - tier.Resolution)
+		rollups, err := s.rr.Range(ctx, q.ID, q.Type, q.Labels, resolutionSeconds(tier), from, end)
+		if err != nil {
+			return nil, errs.Wrap(err, "range rollups")
+		}
+		samples = rollupSamples[int64](rollups)
+		series.Resolution = resolutionSeconds(tier)
+	} else {
+		var err error
+		samples, err = s.cr.Range(ctx, q.ID, q.Type, q.Labels, start.Add(-window), end)
+		if err != nil {
+			return nil, errs.Wrap(err, "range counter samples")
+		}
+	}
+
+	series.Func = q.Func
+	series.Points = counterFuncAtStep(samples, q.Func, start, end, step, window)
+	return series, nil
+}
+
+// counterFuncAtStep expects samples ordered by timestamp.
+//
+// The increase at a step sums differences of consecutive samples within the window before it,
+// a value lower than the previous one is counted from zero as the counter was reset.
+// Steps with less than two samples in the window are omitted.
+func counterFuncAtStep(
+	samples []model.Sample[int64],
+	fn string,
+	start, end time.Time,
+	step, window time.Duration,
+) []*model.Point {
+	points := make([]*model.Point, 0, int(end.Sub(start)/step)+1)
+
+	// total[i] is the increase from the first sample to the i-th one.
+	total := make([]float64, len(samples))
+	for i := 1; i < len(samples); i++ {
+		total[i] = total[i-1] + increase(float64(samples[i-1].Value), float64(samples[i].Value))
+	}
+
+	var lo, hi int
+	for ts := start; !ts.After(end); ts = ts.Add(step) {
+		for hi < len(samples) && !samples[hi].Timestamp.After(ts) {
+			hi++
+		}
+		for lo < hi && !samples[lo].Timestamp.After(ts.Add(-window)) {
+			lo++
+		}
+
+		if hi-lo < 2 {
+			continue
+		}
+
+		value := total[hi-1] - total[lo]
+		if fn == model.FuncRate {
+			value /= window.Seconds()
+		}
+		points = append(points, &model.Point{Timestamp: ts.Unix(), Value: &value})
+	}
+
+	return points
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestService_QueryRange_counterFunc(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	sample := func(offsetSec int64, value int64) model.Sample[int64] {
+		return model.Sample[int64]{
+			ID:        "PollCount",
+			Type:      model.Counter,
+			Value:     value,
+			Timestamp: start.Add(time.Duration(offsetSec) * time.Second),
+		}
+	}
+	samples := []model.Sample[int64]{
+		sample(-50, 10),
+		sample(-30, 20),
+		sample(-10, 40),
+		sample(10, 60),
+		sample(30, 5),
+		sample(50, 25),
+	}
+
+	tests := []struct {
+		name    string
+		query   *model.RangeQuery
+		samples []model.Sample[int64]
+		want    []*model.Point
+		wantErr error
+	}{
+		{
+			name: "Increase over step skips windows with a single sample",
+			query: &model.RangeQuery{
+				ID:    "PollCount",
+				Type:  model.Counter,
+				Start: 1000,
+				End:   1060,
+				Step:  30,
+				Func:  model.FuncIncrease,
+			},
+			samples: samples,
+			want: []*model.Point{
+				{Timestamp: 1030, Value: pkg.Ptr(5.0)},
+			},
+		},
+		{
+			name: "Increase over window handles counter reset",
+			query: &model.RangeQuery{
+				ID:     "PollCount",
+				Type:   model.Counter,
+				Start:  1000,
+				End:    1060,
+				Step:   30,
+				Window: 60,
+				Func:   model.FuncIncrease,
+			},
+			samples: samples,
+			want: []*model.Point{
+				{Timestamp: 1000, Value: pkg.Ptr(30.0)},
+				{Timestamp: 1030, Value: pkg.Ptr(25.0)},
+				{Timestamp: 1060, Value: pkg.Ptr(25.0)},
+			},
+		},
+		{
+			name: "Rate over window",
+			query: &model.RangeQuery{
+				ID:     "PollCount",
+				Type:   model.Counter,
+				Start:  1000,
+				End:    1000,
+				Step:   30,
+				Window: 60,
+				Func:   model.FuncRate,
+			},
+			samples: samples,
+			want: []*model.Point{
+				{Timestamp: 1000, Value: pkg.Ptr(0.5)},
+			},
+		},
+		{
+			name: "Unknown function",
+			query: &model.RangeQuery{
+				ID:    "PollCount",
+				Type:  model.Counter,
+				Start: 1000,
+				End:   1060,
+				Step:  30,
+				Func:  "avg",
+			},
+			wantErr: errs.ErrInvalidQueryFunc,
+		},
+		{
+			name: "Rate of gauge",
+			query: &model.RangeQuery{
+				ID:    "PollCount",
+				Type:  model.Gauge,
+				Start: 1000,
+				End:   1060,
+				Step:  30,
+				Func:  model.FuncRate,
+			},
+			wantErr: errs.ErrInvalidMetricType,
+		},
+		{
+			name: "Negative window",
+			query: &model.RangeQuery{
+				ID:     "PollCount",
+				Type:   model.Counter,
+				Start:  1000,
+				End:    1060,
+				Step:   30,
+				Window: -1,
+				Func:   model.FuncRate,
+			},
+			wantErr: errs.ErrInvalidTimeRange,
+		},
+		{
+			name: "Window overflowing duration",
+			query: &model.RangeQuery{
+				ID:     "PollCount",
+				Type:   model.Counter,
+				Start:  1000,
+				End:    1060,
+				Step:   30,
+				Window: 1e10,
+				Func:   model.FuncRate,
+			},
+			wantErr: errs.ErrInvalidTimeRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cr := &mocks.MockCounterRepo{}
+			if tt.samples != nil {
+				cr.On("Range", mock.Anything, "PollCount", model.Counter, model.Labels(nil),
+					mock.Anything, mock.Anything).
+					Return(tt.samples, nil)
+			}
+
+			s := NewService(nil, cr, nil, nil, nil)
+			s.EnableHistory()
+
+			got, err := s.QueryRange(context.Background(), tt.query)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.query.Func, got.Func)
+			assert.Equal(t, tt.want, got.Points)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// EnableRetention makes ApplyRetention downsample and expire the history according to the policy.
+func (s *Service) EnableRetention(policy *model.RetentionPolicy, rr RollupRepo) {
+	s.retention = policy
+	s.rr = rr
+	s.rolledUp = make(map[time.Duration]time.Time, len(policy.Tiers))
+}
+
+// ApplyRetention rolls up intervals completed by now into every tier and removes data the policy no longer keeps.
+//
+// Every tier is computed from the previous one, the first one from raw samples. It is not safe for concurrent use.
+func (s *Service) ApplyRetention(ctx context.Context, now time.Time) error {
+	if !s.history || s.retention == nil {
+		return nil
+	}
+
+	gauges, err := s.gr.List(ctx)
+	if err != nil {
+		return errs.Wrap(err, "list gauges")
+	}
+	counters, err := s.cr.List(ctx)
+	if err != nil {
+		return errs.Wrap(err, "list counters")
+	}
+
+	var source model.RollupTier
+	for _, tier := range s.retention.Tiers {
+		to := now.Truncate(tier.Resolution)
+		from, ok := s.rolledUp[tier.Resolution]
+		if !ok {
+			from = to.Add(-tier.Keep).Truncate(tier.Resolution)
+		}
+
+		if from.Before(to) {
+			for _, g := range gauges {
+				if err := s.rollupSeries(ctx, g.ID, g.Type, g.Labels, source, tier, from, to); err != nil {
+					return errs.Wrap(err, "roll up gauge "+g.ID)
+				}
+			}
+			for _, c := range counters {
+				if err := s.rollupSeries(ctx, c.ID, c.Type, c.Labels, source, tier, from, to); err != nil {
+					return errs.Wrap(err, "roll up counter "+c.ID)
+				}
+			}
+			s.rolledUp[tier.Resolution] = to
+		}
+
+		source = tier
+	}
+
+	return s.expire(ctx, now)
+}
+
+func (s *Service) expire(ctx context.Context, now time.Time) error {
+	if s.retention.Raw > 0 {
+		before := now.Add(-s.retention.Raw)
+		if err := s.gr.DeleteSamplesBefore(ctx, model.Gauge, before); err != nil {
+			return errs.Wrap(err, "delete gauge samples")
+		}
+		if err := s.cr.DeleteSamplesBefore(ctx, model.Counter, before); err != nil {
+			return errs.Wrap(err, "delete counter samples")
+		}
+	}
+
+	for _, tier := range s.retention.Tiers {
+		if err := s.rr.DeleteBefore(ctx, resolutionSeconds(tier), now.Add(-tier.Keep)); err != nil {
+			return errs.Wrap(err, "delete rollups of "+tier.Resolution.String())
+		}
+	}
+
+	return nil
+}
+
+// rollupSeries computes rollups of a series for the intervals in [from, to) out of the source tier data,
+// zero source tier stands for raw samples.
+func (s *Service) rollupSeries(
+	ctx context.Context,
+	metricID, metricType string,
+	labels model.Labels,
+	source, tier model.RollupTier,
+	from, to time.Time,
+) error {
+	var (
+		units []model.Rollup
+		err   error
+	)
+
+	switch {
+	case source.Resolution > 0:
+		units, err = s.rr.Range(ctx, metricID, metricType, labels, resolutionSeconds(source), from, to)
+	case metricType == model.Gauge:
+		units, err = rawUnits(ctx, s.gr, metricID, metricType, labels, from, to)
+	default:
+		units, err = rawUnits(ctx, s.cr, metricID, metricType, labels, from, to)
+	}
+	if err != nil {
+		return errs.Wrap(err, "range source data")
+	}
+
+	rollups := mergeRollups(units, metricType, tier, from, to)
+	if len(rollups) == 0 {
+		return nil
+	}
+	return errs.Wrap(s.rr.Save(ctx, rollups...), "save rollups")
+}
+
+// rawUnits turns every raw sample into a rollup of a single observation, so all tiers are merged the same way.
+//
+// The increase of a counter is counted from the previous sample, a value below it means the counter was reset.
+// The first sample is compared with the latest one recorded before from, however long ago it was.
+func rawUnits[T int64 | float64](
+	ctx context.Context,
+	repo metricRepo[T],
+	metricID, metricType string,
+	labels model.Labels,
+	from, to time.Time,
+) ([]model.Rollup, error) {
+	samples, err := repo.Range(ctx, metricID, metricType, labels, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *model.Sample[T]
+	if metricType == model.Counter && len(samples) > 0 {
+		prev, err = repo.LatestBefore(ctx, metricID, metricType, labels, from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	units := make([]model.Rollup, 0, len(samples))
+	for i, sample := range samples {
+		value := float64(sample.Value)
+		unit := model.Rollup{
+			ID:        metricID,
+			Type:      metricType,
+			Labels:    labels,
+			Timestamp: sample.Timestamp,
+			Count:     1,
+			Last:      value,
+		}
+
+		if metricType == model.Gauge {
+			unit.Min, unit.Max, unit.Avg = value, value, value
+		} else if prev != nil {
+			unit.Sum = increase(float64(prev.Value), value)
+		}
+
+		units = append(units, unit)
+		prev = &samples[i]
+	}
+
+	return units, nil
+}
+
+func increase(prev, cur float64) float64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// mergeRollups expects units ordered by timestamp and skips the ones outside [from, to).
+func mergeRollups(units []model.Rollup, metricType string, tier model.RollupTier, from, to time.Time) []model.Rollup {
+	var rollups []model.Rollup
+
+	for _, unit := range units {
+		if unit.Timestamp.Before(from) || !unit.Timestamp.Before(to) {
+			continue
+		}
+
+		ts := unit.Timestamp.Truncate(tier.Resolution)
+		if len(rollups) == 0 || !rollups[len(rollups)-1].Timestamp.Equal(ts) {
+			unit.Timestamp = ts
+			unit.Resolution = resolutionSeconds(tier)
+			rollups = append(rollups, unit)
+			continue
+		}
+
+		r := &rollups[len(rollups)-1]
+		r.Avg = (r.Avg*float64(r.Count) + unit.Avg*float64(unit.Count)) / float64(r.Count+unit.Count)
+		r.Min = min(r.Min, unit.Min)
+		r.Max = max(r.Max, unit.Max)
+		r.Count += unit.Count
+		r.Last = unit.Last
+		r.Sum += unit.Sum
+	}
+
+	if metricType == model.Counter {
+		for i := range rollups {
+			rollups[i].Rate = rollups[i].Sum / tier.Resolution.Seconds()
+		}
+	}
+
+	return rollups
+}
+
+// rollupTier returns the tier serving history since start when raw samples recorded then are already removed.
+func (s *Service) rollupTier(start time.Time) (model.RollupTier, bool) {
+	if s.retention == nil || s.retention.Raw == 0 {
+		return model.RollupTier{}, false
+	}
+
+	now := time.Now()
+	if !start.Before(now.Add(-s.retention.Raw)) {
+		return model.RollupTier{}, false
+	}
+	return s.retention.TierFor(start, now)
+}
+
+// rollupSamples represents every rollup by its last value observed at the end of the interval.
+func rollupSamples[T int64 | float64](rollups []model.Rollup) []model.Sample[T] {
+	samples := make([]model.Sample[T], 0, len(rollups))
+	for _, r := range rollups {
+		samples = append(samples, model.Sample[T]{
+			ID:        r.ID,
+			Type:      r.Type,
+			Labels:    r.Labels,
+			Value:     T(r.Last),
+			Timestamp: r.Timestamp.Add(time.Duration(r.Resolution) * time.Second),
+		})
+	}
+	return samples
+}
+
+func resolutionSeconds(tier model.RollupTier) int64 {
+	return int64(tier.Resolution / time.Second)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/internal/server/repository"
+	"github.com/yogenyslav/ya-metrics/pkg"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestService_ApplyRetention(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	now := base.Add(time.Hour + 30*time.Second)
+	at := func(offsetSec int) time.Time {
+		return base.Add(time.Duration(offsetSec) * time.Second)
+	}
+	policy := &model.RetentionPolicy{
+		Raw:   10 * time.Minute,
+		Tiers: []model.RollupTier{{Resolution: time.Minute, Keep: time.Hour}},
+	}
+
+	gr := &mocks.MockGaugeRepo{}
+	cr := &mocks.MockCounterRepo{}
+	rr := &mocks.MockRollupRepo{}
+
+	gr.On("List", mock.Anything).Return([]model.Metrics[float64]{{ID: "load", Type: model.Gauge}}, nil)
+	cr.On("List", mock.Anything).Return([]model.Metrics[int64]{{ID: "requests", Type: model.Counter}}, nil)
+	gr.On("Range", mock.Anything, "load", model.Gauge, model.Labels(nil), at(0), at(3600)).
+		Return([]model.Sample[float64]{
+			{Value: 1, Timestamp: at(10)},
+			{Value: 3, Timestamp: at(20)},
+			{Value: 2, Timestamp: at(70)},
+		}, nil)
+	rr.On("Save", mock.Anything, []model.Rollup{
+		{ID: "load", Type: model.Gauge, Resolution: 60, Timestamp: at(0), Count: 2, Min: 1, Max: 3, Avg: 2, Last: 3},
+		{ID: "load", Type: model.Gauge, Resolution: 60, Timestamp: at(60), Count: 1, Min: 2, Max: 2, Avg: 2, Last: 2},
+	}).Return(nil)
+	cr.On("Range", mock.Anything, "requests", model.Counter, model.Labels(nil), at(0), at(3600)).
+		Return([]model.Sample[int64]{
+			{Value: 8, Timestamp: at(10)},
+			{Value: 2, Timestamp: at(20)},
+			{Value: 6, Timestamp: at(70)},
+		}, nil)
+	// the baseline of the first increase is found however long before the interval it was recorded
+	cr.On("LatestBefore", mock.Anything, "requests", model.Counter, model.Labels(nil), at(0)).
+		Return(&model.Sample[int64]{Value: 5, Timestamp: at(-600)}, nil)
+	counterRollup := func(offsetSec int, count int64, last, sum float64) model.Rollup {
+		return model.Rollup{
+			ID:         "requests",
+			Type:       model.Counter,
+			Resolution: 60,
+			Timestamp:  at(offsetSec),
+			Count:      count,
+			Last:       last,
+			Sum:        sum,
+			Rate:       sum / 60,
+		}
+	}
+	rr.On("Save", mock.Anything, []model.Rollup{
+		counterRollup(0, 2, 2, 5),
+		counterRollup(60, 1, 6, 4),
+	}).Return(nil)
+	gr.On("DeleteSamplesBefore", mock.Anything, model.Gauge, now.Add(-10*time.Minute)).Return(nil)
+	cr.On("DeleteSamplesBefore", mock.Anything, model.Counter, now.Add(-10*time.Minute)).Return(nil)
+	rr.On("DeleteBefore", mock.Anything, int64(60), now.Add(-time.Hour)).Return(nil)
+
+	s := NewService(gr, cr, nil, nil, nil)
+	s.EnableHistory()
+	s.EnableRetention(policy, rr)
+
+	require.NoError(t, s.ApplyRetention(context.Background(), now))
+	gr.AssertExpectations(t)
+	cr.AssertExpectations(t)
+	rr.AssertExpectations(t)
+
+	// intervals rolled up already are not computed again
+	later := now.Add(10 * time.Second)
+	gr.On("List", mock.Anything).Return([]model.Metrics[float64]{{ID: "load", Type: model.Gauge}}, nil)
+	cr.On("List", mock.Anything).Return([]model.Metrics[int64]{{ID: "requests", Type: model.Counter}}, nil)
+	gr.On("DeleteSamplesBefore", mock.Anything, model.Gauge, later.Add(-10*time.Minute)).Return(nil)
+	cr.On("DeleteSamplesBefore", mock.Anything, model.Counter, later.Add(-10*time.Minute)).Return(nil)
+	rr.On("DeleteBefore", mock.Anything, int64(60), later.Add(-time.Hour)).Return(nil)
+
+	require.NoError(t, s.ApplyRetention(context.Background(), later))
+	gr.AssertExpectations(t)
+	cr.AssertExpectations(t)
+	rr.AssertExpectations(t)
+}
+
+func Test_rawUnits(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	repo := repository.NewMetricInMemRepo[int64](nil)
+	for offset, value := range map[time.Duration]int64{-2 * time.Hour: 5, 10 * time.Second: 8} {
+		require.NoError(t, repo.Append(context.Background(), &model.Sample[int64]{
+			ID:        "requests",
+			Type:      model.Counter,
+			Value:     value,
+			Timestamp: base.Add(offset),
+		}))
+	}
+
+	units, err := rawUnits(context.Background(), repo, "requests", model.Counter, nil, base, base.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, units, 1)
+	assert.Equal(t, 3.0, units[0].Sum)
+
+	units, err = rawUnits(context.Background(), repo, "requests", model.Counter, nil, base.Add(-3*time.Hour), base)
+	require.NoError(t, err)
+	require.Len(t, units, 1)
+	assert.Zero(t, units[0].Sum)
+}
+
+func Test_mergeRollups(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	tier := model.RollupTier{Resolution: 5 * time.Minute, Keep: 24 * time.Hour}
+	minute := func(offsetMin int, count int64, minV, maxV, avg, last float64) model.Rollup {
+		return model.Rollup{
+			ID:         "load",
+			Type:       model.Gauge,
+			Resolution: 60,
+			Timestamp:  base.Add(time.Duration(offsetMin) * time.Minute),
+			Count:      count,
+			Min:        minV,
+			Max:        maxV,
+			Avg:        avg,
+			Last:       last,
+		}
+	}
+
+	got := mergeRollups(
+		[]model.Rollup{
+			minute(-1, 1, 9, 9, 9, 9),
+			minute(0, 1, 1, 1, 1, 1),
+			minute(2, 3, 2, 6, 4, 5),
+			minute(7, 2, 0, 4, 2, 0),
+			minute(10, 1, 5, 5, 5, 5),
+		},
+		model.Gauge,
+		tier,
+		base,
+		base.Add(10*time.Minute),
+	)
+
+	assert.Equal(t, []model.Rollup{
+		{ID: "load", Type: model.Gauge, Resolution: 300, Timestamp: base, Count: 4, Min: 1, Max: 6, Avg: 3.25, Last: 5},
+		{
+			ID:         "load",
+			Type:       model.Gauge,
+			Resolution: 300,
+			Timestamp:  base.Add(5 * time.Minute),
+			Count:      2,
+			Min:        0,
+			Max:        4,
+			Avg:        2,
+			Last:       0,
+		},
+	}, got)
+}
+
+func TestService_QueryRange_rollups(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Minute)
+	policy := &model.RetentionPolicy{
+		Raw:   time.Hour,
+		Tiers: []model.RollupTier{{Resolution: time.Minute, Keep: 24 * time.Hour}},
+	}
+
+	rr := &mocks.MockRollupRepo{}
+	rr.On("Range", mock.Anything, "load", model.Gauge, model.Labels(nil), int64(60), mock.Anything, mock.Anything).
+		Return([]model.Rollup{
+			{ID: "load", Type: model.Gauge, Resolution: 60, Timestamp: start.Add(-time.Minute), Last: 1},
+			{ID: "load", Type: model.Gauge, Resolution: 60, Timestamp: start.Add(time.Minute), Last: 2},
+		}, nil)
+
+	s := NewService(nil, nil, nil, nil, nil)
+	s.EnableHistory()
+	s.EnableRetention(policy, rr)
+
+	got, err := s.QueryRange(context.Background(), &model.RangeQuery{
+		ID:    "load",
+		Type:  model.Gauge,
+		Start: start.Unix(),
+		End:   start.Add(2 * time.Minute).Unix(),
+		Step:  60,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), got.Resolution)
+	assert.Equal(t, []*model.Point{
+		{Timestamp: start.Unix(), Value: pkg.Ptr(1.0)},
+		{Timestamp: start.Add(2 * time.Minute).Unix(), Value: pkg.Ptr(2.0)},
+	}, got.Points)
+}
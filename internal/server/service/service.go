@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg/database"
@@ -9,8 +10,22 @@ import (
 )
 
 type metricRepo[T int64 | float64] interface {
-	Get(ctx context.Context, metricID, metricType string) (*model.Metrics[T], error)
+	Get(ctx context.Context, metricID, metricType string, labels model.Labels) (*model.Metrics[T], error)
 	List(ctx context.Context) ([]model.Metrics[T], error)
+	Append(ctx context.Context, s *model.Sample[T]) error
+	Range(
+		ctx context.Context,
+		metricID, metricType string,
+		labels model.Labels,
+		from, to time.Time,
+	) ([]model.Sample[T], error)
+	LatestBefore(
+		ctx context.Context,
+		metricID, metricType string,
+		labels model.Labels,
+		before time.Time,
+	) (*model.Sample[T], error)
+	DeleteSamplesBefore(ctx context.Context, metricType string, before time.Time) error
 }
 
 // GaugeRepo is the interface for gauge metric repository.
@@ -22,23 +37,66 @@ type GaugeRepo interface {
 // CounterRepo is the interface for counter metric repository.
 type CounterRepo interface {
 	metricRepo[int64]
-	Update(ctx context.Context, m *model.Metrics[int64]) error
+	// Update adds the delta to the counter and returns the new total.
+	Update(ctx context.Context, m *model.Metrics[int64]) (int64, error)
+}
+
+// HistogramRepo is the interface for histogram metric repository.
+type HistogramRepo interface {
+	Get(ctx context.Context, metricID string, labels model.Labels) (*model.HistogramMetric, error)
+	List(ctx context.Context) ([]model.HistogramMetric, error)
+	Merge(ctx context.Context, h *model.HistogramMetric) error
+}
+
+// SummaryRepo is the interface for summary metric repository.
+type SummaryRepo interface {
+	Get(ctx context.Context, metricID string, labels model.Labels) (*model.SummaryMetric, error)
+	List(ctx context.Context) ([]model.SummaryMetric, error)
+	Merge(ctx context.Context, s *model.SummaryMetric) error
+}
+
+// RollupRepo is the interface for downsampled metric history repository.
+type RollupRepo interface {
+	Save(ctx context.Context, rollups ...model.Rollup) error
+	Range(
+		ctx context.Context,
+		metricID, metricType string,
+		labels model.Labels,
+		resolution int64,
+		from, to time.Time,
+	) ([]model.Rollup, error)
+	DeleteBefore(ctx context.Context, resolution int64, before time.Time) error
 }
 
 // Service provides metric-related operations.
 type Service struct {
 	gr          GaugeRepo
 	cr          CounterRepo
+	hr          HistogramRepo
+	sr          SummaryRepo
 	uow         database.UnitOfWork
+	history     bool
+	retention   *model.RetentionPolicy
+	rr          RollupRepo
+	rolledUp    map[time.Duration]time.Time
+	staleAfter  time.Duration
 	counterPool *pool.Pool[*model.Metrics[int64]]
 	gaugePool   *pool.Pool[*model.Metrics[float64]]
 }
 
 // NewService creates a new Service instance.
-func NewService(gr GaugeRepo, cr CounterRepo, uow database.UnitOfWork) *Service {
+func NewService(
+	gr GaugeRepo,
+	cr CounterRepo,
+	hr HistogramRepo,
+	sr SummaryRepo,
+	uow database.UnitOfWork,
+) *Service {
 	return &Service{
 		gr:  gr,
 		cr:  cr,
+		hr:  hr,
+		sr:  sr,
 		uow: uow,
 		counterPool: pool.New(func() *model.Metrics[int64] {
 			return &model.Metrics[int64]{}
@@ -48,3 +106,8 @@ func NewService(gr GaugeRepo, cr CounterRepo, uow database.UnitOfWork) *Service
 		}),
 	}
 }
+
+// EnableHistory makes the service record a timestamped sample on every metric update.
+func (s *Service) EnableHistory() {
+	s.history = true
+}
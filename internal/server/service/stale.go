@@ -0,0 +1,18 @@
+package service
+
+import (
+	"time"
+
+	"github.com/yogenyslav/ya-metrics/internal/model"
+)
+
+// MarkStaleAfter makes series not updated for longer than d reported as stale, zero d disables it.
+func (s *Service) MarkStaleAfter(d time.Duration) {
+	s.staleAfter = d
+}
+
+// markStale marks the metric stale if it is not updated for too long, metrics with unknown update time are not.
+func (s *Service) markStale(m *model.MetricsDto, now time.Time) *model.MetricsDto {
+	m.Stale = s.staleAfter > 0 && m.UpdatedAt != nil && now.Sub(*m.UpdatedAt) > s.staleAfter
+	return m
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/model"
+	"github.com/yogenyslav/ya-metrics/tests/mocks"
+)
+
+func TestService_ListMetrics_stale(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	fresh := now.Add(-time.Second)
+	old := now.Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		staleAfter time.Duration
+		want       map[string]bool
+	}{
+		{
+			name:       "Series not updated for too long are stale",
+			staleAfter: time.Minute,
+			want: map[string]bool{
+				"fresh": false, "old": true, "restored": false,
+				"PollCount": true, "latency": true, "duration": false,
+			},
+		},
+		{
+			name: "Staleness disabled",
+			want: map[string]bool{
+				"fresh": false, "old": false, "restored": false,
+				"PollCount": false, "latency": false, "duration": false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			gr := &mocks.MockGaugeRepo{}
+			gr.On("List", mock.Anything).Return([]model.Metrics[float64]{
+				{ID: "fresh", Type: model.Gauge, UpdatedAt: fresh},
+				{ID: "old", Type: model.Gauge, UpdatedAt: old},
+				{ID: "restored", Type: model.Gauge},
+			}, nil)
+			cr := &mocks.MockCounterRepo{}
+			cr.On("List", mock.Anything).Return([]model.Metrics[int64]{
+				{ID: "PollCount", Type: model.Counter, UpdatedAt: old},
+			}, nil)
+			hr := &mocks.MockHistogramRepo{}
+			hr.On("List", mock.Anything).Return([]model.HistogramMetric{
+				{ID: "latency", UpdatedAt: old, HistogramData: model.HistogramData{Counts: []int64{0}}},
+			}, nil)
+			summary := model.NewSummaryMetric("duration", nil, 1)
+			summary.UpdatedAt = fresh
+			sr := &mocks.MockSummaryRepo{}
+			sr.On("List", mock.Anything).Return([]model.SummaryMetric{*summary}, nil)
+
+			s := NewService(gr, cr, hr, sr, nil)
+			s.MarkStaleAfter(tt.staleAfter)
+
+			metrics, err := s.ListMetrics(context.Background())
+			require.NoError(t, err)
+
+			got := make(map[string]bool)
+			for _, m := range metrics {
+				got[m.ID] = m.Stale
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, &old, metrics[1].UpdatedAt)
+			assert.Nil(t, metrics[2].UpdatedAt)
+		})
+	}
+}
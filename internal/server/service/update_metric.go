@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"math"
+	"time"
 
 	"github.com/yogenyslav/ya-metrics/internal/model"
 	"github.com/yogenyslav/ya-metrics/pkg/errs"
@@ -17,8 +19,13 @@ func (s *Service) UpdateMetric(ctx context.Context, req *model.MetricsDto) error
 		m.ID = req.ID
 		m.Type = model.Counter
 		m.Value = *req.Delta
+		m.Labels = req.Labels
 
-		return s.cr.Update(ctx, m)
+		total, err := s.cr.Update(ctx, m)
+		if err != nil {
+			return err
+		}
+		return s.recordCounter(ctx, m, total, time.Now())
 	case model.Gauge:
 		m := s.gaugePool.Get()
 		defer s.gaugePool.Put(m)
@@ -26,8 +33,25 @@ func (s *Service) UpdateMetric(ctx context.Context, req *model.MetricsDto) error
 		m.ID = req.ID
 		m.Type = model.Gauge
 		m.Value = *req.Value
+		m.Labels = req.Labels
 
-		return s.gr.Set(ctx, m)
+		if err := s.gr.Set(ctx, m); err != nil {
+			return err
+		}
+		return s.recordGauge(ctx, m, time.Now())
+	case model.Histogram:
+		if req.Histogram == nil {
+			return errs.Wrap(errs.ErrInvalidMetricValue, "no histogram provided")
+		}
+		if err := req.Histogram.Validate(); err != nil {
+			return errs.Wrap(errs.ErrInvalidMetricValue, err.Error())
+		}
+		return s.hr.Merge(ctx, req.ToHistogramMetric())
+	case model.Summary:
+		if req.Value == nil || math.IsNaN(*req.Value) || math.IsInf(*req.Value, 0) {
+			return errs.Wrap(errs.ErrInvalidMetricValue, "summary observation must be a finite value")
+		}
+		return s.sr.Merge(ctx, model.NewSummaryMetric(req.ID, req.Labels, *req.Value))
 	}
 	return errs.Wrap(errs.ErrInvalidMetricType)
 }
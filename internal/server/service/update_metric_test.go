@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -97,7 +98,7 @@ func TestService_UpdateMetric(t *testing.T) {
 				gr := &mocks.MockGaugeRepo{}
 				cr := &mocks.MockCounterRepo{}
 
-				s := NewService(gr, cr, nil)
+				s := NewService(gr, cr, nil, nil, nil)
 
 				switch tt.args.req.Type {
 				case model.Gauge:
@@ -119,12 +120,12 @@ func TestService_UpdateMetric(t *testing.T) {
 							ID:    tt.args.req.ID,
 							Type:  model.Counter,
 							Value: *tt.args.req.Delta,
-						}).Return(nil)
+						}).Return(*tt.args.req.Delta, nil)
 					} else {
 						cr.On("Update", mock.Anything, &model.Metrics[int64]{
 							ID:   tt.args.req.ID,
 							Type: model.Counter,
-						}).Return(errs.ErrInvalidMetricValue)
+						}).Return(int64(0), errs.ErrInvalidMetricValue)
 					}
 				}
 
@@ -139,6 +140,111 @@ func TestService_UpdateMetric(t *testing.T) {
 	}
 }
 
+func TestService_UpdateMetric_histogram(t *testing.T) {
+	t.Parallel()
+
+	valid := &model.HistogramData{
+		Bounds: []float64{0.1, 0.5, 1},
+		Counts: []int64{3, 2, 1, 0},
+		Count:  6,
+		Sum:    2.4,
+	}
+
+	tests := []struct {
+		name      string
+		histogram *model.HistogramData
+		wantErr   error
+	}{
+		{name: "Valid histogram", histogram: valid},
+		{name: "No histogram", histogram: nil, wantErr: errs.ErrInvalidMetricValue},
+		{
+			name:      "Counts do not match bounds",
+			histogram: &model.HistogramData{Bounds: []float64{1}, Counts: []int64{1}, Count: 1},
+			wantErr:   errs.ErrInvalidMetricValue,
+		},
+		{
+			name:      "Bounds are not increasing",
+			histogram: &model.HistogramData{Bounds: []float64{1, 1}, Counts: []int64{1, 0, 0}, Count: 1},
+			wantErr:   errs.ErrInvalidMetricValue,
+		},
+		{
+			name:      "Count differs from buckets",
+			histogram: &model.HistogramData{Bounds: []float64{1}, Counts: []int64{1, 1}, Count: 3},
+			wantErr:   errs.ErrInvalidMetricValue,
+		},
+		{
+			name:      "Negative bucket count",
+			histogram: &model.HistogramData{Bounds: []float64{1}, Counts: []int64{2, -1}, Count: 1},
+			wantErr:   errs.ErrInvalidMetricValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hr := &mocks.MockHistogramRepo{}
+			if tt.wantErr == nil {
+				hr.On("Merge", mock.Anything, &model.HistogramMetric{
+					ID:            "latency",
+					HistogramData: *tt.histogram,
+				}).Return(nil)
+			}
+
+			s := NewService(nil, nil, hr, nil, nil)
+			err := s.UpdateMetric(context.Background(), &model.MetricsDto{
+				ID:        "latency",
+				Type:      model.Histogram,
+				Histogram: tt.histogram,
+			})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			hr.AssertExpectations(t)
+		})
+	}
+}
+func TestService_UpdateMetric_summary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   *float64
+		wantErr error
+	}{
+		{name: "Valid observation", value: pkg.Ptr(0.25)},
+		{name: "No value", value: nil, wantErr: errs.ErrInvalidMetricValue},
+		{name: "NaN value", value: pkg.Ptr(math.NaN()), wantErr: errs.ErrInvalidMetricValue},
+		{name: "Infinite value", value: pkg.Ptr(math.Inf(1)), wantErr: errs.ErrInvalidMetricValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sr := &mocks.MockSummaryRepo{}
+			if tt.wantErr == nil {
+				sr.On("Merge", mock.Anything, model.NewSummaryMetric("request_duration", nil, *tt.value)).Return(nil)
+			}
+
+			s := NewService(nil, nil, nil, sr, nil)
+			err := s.UpdateMetric(context.Background(), &model.MetricsDto{
+				ID:    "request_duration",
+				Type:  model.Summary,
+				Value: tt.value,
+			})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			sr.AssertExpectations(t)
+		})
+	}
+}
+
 func TestService_UpdateMetricsBatch(t *testing.T) {
 	t.Parallel()
 
@@ -150,7 +256,7 @@ func TestService_UpdateMetricsBatch(t *testing.T) {
 		cr := new(mocks.MockCounterRepo)
 		uow := mocks.NewMockUnitOfWork(gomock.NewController(t))
 
-		s := NewService(gr, cr, uow)
+		s := NewService(gr, cr, nil, nil, uow)
 		metrics := []*model.MetricsDto{
 			{
 				ID:    "gauge_metric",
@@ -179,7 +285,7 @@ func TestService_UpdateMetricsBatch(t *testing.T) {
 			ID:    metrics[1].ID,
 			Type:  model.Counter,
 			Value: *metrics[1].Delta,
-		}).Return(nil)
+		}).Return(*metrics[1].Delta, nil)
 
 		err := s.UpdateMetricsBatch(ctx, metrics)
 		require.NoError(t, err)
@@ -193,7 +299,7 @@ func TestService_UpdateMetricsBatch(t *testing.T) {
 		cr := new(mocks.MockCounterRepo)
 		uow := mocks.NewMockUnitOfWork(gomock.NewController(t))
 
-		s := NewService(gr, cr, uow)
+		s := NewService(gr, cr, nil, nil, uow)
 		metrics := []*model.MetricsDto{
 			{
 				ID:    "gauge_metric",
@@ -222,7 +328,7 @@ func TestService_UpdateMetricsBatch(t *testing.T) {
 			ID:    metrics[1].ID,
 			Type:  model.Counter,
 			Value: *metrics[1].Delta,
-		}).Return(errs.ErrInvalidMetricValue)
+		}).Return(int64(0), errs.ErrInvalidMetricValue)
 
 		err := s.UpdateMetricsBatch(ctx, metrics)
 		require.Error(t, err)
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/internal/config"
+)
+
+func TestServer_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("In-flight request is drained before the final dump", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var handled, dumpedAfterHandled atomic.Bool
+
+		s := &Server{
+			cfg: &config.Config{Dump: &config.DumpConfig{StoreInterval: 1}},
+		}
+		s.http = &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				close(started)
+				<-release
+				handled.Store(true)
+				w.WriteHeader(http.StatusOK)
+			}),
+		}
+		s.dumpOnShutdown = func() {
+			dumpedAfterHandled.Store(handled.Load())
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ctx, s.stopJobs = context.WithCancel(ctx)
+
+		ticker := &testTicker{ch: make(chan time.Time)}
+		s.Dumping(ctx, &mockDumper{}, func(time.Duration) Ticker { return ticker })
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go s.http.Serve(lis)
+
+		respErr := make(chan error, 1)
+		go func() {
+			resp, err := http.Get("http://" + lis.Addr().String())
+			if err == nil {
+				resp.Body.Close()
+			}
+			respErr <- err
+		}()
+		<-started
+
+		shutdownErr := make(chan error, 1)
+		go func() {
+			shutdownErr <- s.Shutdown(context.Background())
+		}()
+
+		select {
+		case <-shutdownErr:
+			t.Fatal("shutdown returned before the request was handled")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		close(release)
+		require.NoError(t, <-shutdownErr)
+		require.NoError(t, <-respErr)
+		assert.True(t, dumpedAfterHandled.Load())
+
+		_, err = http.Get("http://" + lis.Addr().String())
+		assert.Error(t, err)
+	})
+
+	t.Run("Drain timeout", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		s := &Server{cfg: &config.Config{}}
+		s.http = &http.Server{
+			Handler: http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				close(started)
+				<-release
+			}),
+		}
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go s.http.Serve(lis)
+
+		go func() {
+			resp, err := http.Get("http://" + lis.Addr().String())
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err = s.Shutdown(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Nothing started", func(t *testing.T) {
+		t.Parallel()
+
+		s := &Server{cfg: &config.Config{}}
+		assert.NoError(t, s.Shutdown(context.Background()))
+	})
+}
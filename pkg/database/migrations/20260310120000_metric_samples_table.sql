@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+create table metric_samples (
+    id text not null,
+    mtype text not null,
+    ts timestamptz not null,
+    delta bigint,
+    value double precision,
+    primary key (id, mtype, ts)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table metric_samples;
+-- +goose StatementEnd
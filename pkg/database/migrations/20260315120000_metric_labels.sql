@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table metrics add column labels jsonb not null default '{}';
+alter table metrics drop constraint metrics_pkey;
+alter table metrics add primary key (id, labels);
+
+alter table metric_samples add column labels jsonb not null default '{}';
+alter table metric_samples drop constraint metric_samples_pkey;
+alter table metric_samples add primary key (id, mtype, labels, ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+delete from metric_samples where labels <> '{}';
+alter table metric_samples drop constraint metric_samples_pkey;
+alter table metric_samples add primary key (id, mtype, ts);
+alter table metric_samples drop column labels;
+
+delete from metrics where labels <> '{}';
+alter table metrics drop constraint metrics_pkey;
+alter table metrics add primary key (id);
+alter table metrics drop column labels;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+create table histograms (
+    id text not null,
+    labels jsonb not null default '{}',
+    bounds double precision[] not null,
+    counts bigint[] not null,
+    count bigint not null,
+    sum double precision not null,
+    created_at timestamp default current_timestamp not null,
+    updated_at timestamptz default current_timestamp not null,
+    primary key (id, labels)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table histograms;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+create table summaries (
+    id text not null,
+    labels jsonb not null default '{}',
+    sketch jsonb not null,
+    created_at timestamp default current_timestamp not null,
+    updated_at timestamptz default current_timestamp not null,
+    primary key (id, labels)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table summaries;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+create table metric_rollups (
+    id text not null,
+    mtype text not null,
+    labels jsonb not null default '{}',
+    resolution bigint not null,
+    ts timestamptz not null,
+    count bigint not null,
+    min double precision not null,
+    max double precision not null,
+    avg double precision not null,
+    last double precision not null,
+    sum double precision not null,
+    rate double precision not null,
+    primary key (id, mtype, labels, resolution, ts)
+);
+
+create index metric_samples_ts_idx on metric_samples (ts);
+create index metric_rollups_resolution_ts_idx on metric_rollups (resolution, ts);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index metric_samples_ts_idx;
+drop table metric_rollups;
+-- +goose StatementEnd
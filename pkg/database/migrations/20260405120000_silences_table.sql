@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+create table silences (
+    id text primary key,
+    metric_id text not null default '',
+    labels jsonb not null default '{}',
+    starts_at timestamptz not null,
+    ends_at timestamptz not null,
+    comment text not null default '',
+    created_at timestamptz not null
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table silences;
+-- +goose StatementEnd
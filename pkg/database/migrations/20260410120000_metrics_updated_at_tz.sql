@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table metrics alter column updated_at type timestamptz;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table metrics alter column updated_at type timestamp;
+-- +goose StatementEnd
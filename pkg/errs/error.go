@@ -10,6 +10,14 @@ var (
 	ErrInvalidMetricType = errors.New("invalid metric type")
 	// ErrInvalidMetricValue is an error when failed to parse metric value.
 	ErrInvalidMetricValue = errors.New("invalid metric value")
+	// ErrInvalidTimeRange is an error when the requested time range is malformed.
+	ErrInvalidTimeRange = errors.New("invalid time range")
+	// ErrInvalidLabels is an error when a metric label name is malformed.
+	ErrInvalidLabels = errors.New("invalid metric labels")
+	// ErrInvalidQueryFunc is an error when an unknown function is applied to a range query.
+	ErrInvalidQueryFunc = errors.New("invalid query function")
+	// ErrInvalidSilence is an error when a silence has no matcher or a malformed time range.
+	ErrInvalidSilence = errors.New("invalid silence")
 )
 
 // 404.
@@ -18,6 +26,14 @@ var (
 	ErrNoMetricID = errors.New("no metric name provided")
 	// ErrMetricNotFound is an error when the requested metric is not found.
 	ErrMetricNotFound = errors.New("metric not found")
+	// ErrSilenceNotFound is an error when the requested silence is not found.
+	ErrSilenceNotFound = errors.New("silence not found")
+)
+
+// 409.
+var (
+	// ErrBucketsMismatch is an error when histogram buckets differ from the stored ones.
+	ErrBucketsMismatch = errors.New("histogram buckets mismatch")
 )
 
 // 422.
@@ -31,3 +47,9 @@ var (
 	// ErrDatabaseUnavailable is an error when the database is unavailable.
 	ErrDatabaseUnavailable = errors.New("database unavailable")
 )
+
+// 501.
+var (
+	// ErrHistoryDisabled is an error when metric history is requested but not recorded.
+	ErrHistoryDisabled = errors.New("metric history is disabled")
+)
@@ -25,6 +25,8 @@ type Config struct {
 type RetryableFunc func(ctx context.Context) error
 
 // WithLinearBackoffRetry is a wrapper for retry logic with linear backoff.
+//
+// Retries stop when ctx is done, the last error is returned together with the context error.
 func WithLinearBackoffRetry(ctx context.Context, cfg *Config, fn RetryableFunc) error {
 	var err error
 
@@ -43,7 +45,11 @@ func WithLinearBackoffRetry(ctx context.Context, cfg *Config, fn RetryableFunc)
 			return err
 		}
 
-		time.Sleep(time.Millisecond * time.Duration(cfg.LinearBackoffMilli*i))
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(time.Millisecond * time.Duration(cfg.LinearBackoffMilli*i)):
+		}
 	}
 
 	return err
@@ -4,11 +4,33 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/yogenyslav/ya-metrics/pkg/retry"
 )
 
+func TestWithLinearBackoffRetry_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	start := time.Now()
+	err := retry.WithLinearBackoffRetry(ctx, &retry.Config{
+		MaxRetries:         3,
+		LinearBackoffMilli: 1000,
+	}, func(context.Context) error {
+		calls++
+		return errors.New("err")
+	})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 2, calls)
+	require.Less(t, time.Since(start), time.Second)
+}
+
 func TestWithLinearBackoffRetry(t *testing.T) {
 	t.Parallel()
 
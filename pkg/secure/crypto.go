@@ -0,0 +1,173 @@
+package secure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+const (
+	// EncryptionHeader is the request header marking the body as encrypted with the named scheme.
+	EncryptionHeader = "X-Encryption"
+	// EncryptionScheme is a random AES-256-GCM key wrapped with RSA-OAEP SHA-256.
+	EncryptionScheme = "rsa-oaep-aes-256-gcm"
+
+	aesKeySize = 32
+)
+
+// ErrInvalidCiphertext indicates that the data can not be decrypted with the key.
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Encryptor encrypts data with a public RSA key.
+//
+// Every message has its own AES key, the encrypted message is the wrapped key, the nonce and the sealed data.
+type Encryptor struct {
+	key *rsa.PublicKey
+}
+
+// NewEncryptor creates a new Encryptor with the public key.
+func NewEncryptor(key *rsa.PublicKey) *Encryptor {
+	return &Encryptor{key: key}
+}
+
+// Encrypt encrypts the data.
+func (e *Encryptor) Encrypt(data []byte) ([]byte, error) {
+	aesKey := make([]byte, aesKeySize)
+	rand.Read(aesKey)
+
+	wrappedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, e.key, aesKey, nil)
+	if err != nil {
+		return nil, errs.Wrap(err, "wrap key")
+	}
+
+	gcm, err := newGCM(aesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(wrappedKey)+gcm.NonceSize()+len(data)+gcm.Overhead())
+	out = append(out, wrappedKey...)
+	nonce := make([]byte, gcm.NonceSize())
+	rand.Read(nonce)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, nil), nil
+}
+
+// Decryptor decrypts data encrypted by Encryptor with the matching private RSA key.
+type Decryptor struct {
+	key *rsa.PrivateKey
+}
+
+// NewDecryptor creates a new Decryptor with the private key.
+func NewDecryptor(key *rsa.PrivateKey) *Decryptor {
+	return &Decryptor{key: key}
+}
+
+// Decrypt decrypts the data.
+func (d *Decryptor) Decrypt(data []byte) ([]byte, error) {
+	keySize := d.key.Size()
+	if len(data) < keySize {
+		return nil, errs.Wrap(ErrInvalidCiphertext, "message is shorter than the wrapped key")
+	}
+
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), nil, d.key, data[:keySize], nil)
+	if err != nil {
+		return nil, errs.Wrap(errors.Join(ErrInvalidCiphertext, err), "unwrap key")
+	}
+
+	gcm, err := newGCM(aesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[keySize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, errs.Wrap(ErrInvalidCiphertext, "message has no nonce")
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errs.Wrap(errors.Join(ErrInvalidCiphertext, err), "open message")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errs.Wrap(err, "create cipher")
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errs.Wrap(err, "create gcm")
+	}
+	return gcm, nil
+}
+
+// LoadPublicKey reads a PEM encoded RSA public key in PKIX or PKCS #1 form.
+func LoadPublicKey(path string) (*rsa.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errs.Wrap(err, "parse public key")
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// LoadPrivateKey reads a PEM encoded RSA private key in PKCS #8 or PKCS #1 form.
+func LoadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errs.Wrap(err, "parse private key")
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errs.Wrap(err, "read key file")
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data in " + path)
+	}
+	return block, nil
+}
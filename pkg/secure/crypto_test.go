@@ -0,0 +1,130 @@
+package secure
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptor_Decrypt(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	data := []byte(`[{"id":"Alloc","type":"gauge","value":1.5}]`)
+	encrypted, err := NewEncryptor(&key.PublicKey).Encrypt(data)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "Alloc")
+
+	tests := []struct {
+		name    string
+		key     *rsa.PrivateKey
+		data    []byte
+		wantErr bool
+	}{
+		{
+			name: "Matching key",
+			key:  key,
+			data: encrypted,
+		},
+		{
+			name:    "Other key",
+			key:     other,
+			data:    encrypted,
+			wantErr: true,
+		},
+		{
+			name: "Tampered data",
+			key:  key,
+			data: func() []byte {
+				tampered := append([]byte{}, encrypted...)
+				tampered[len(tampered)-1] ^= 1
+				return tampered
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "Truncated data",
+			key:     key,
+			data:    encrypted[:10],
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plain, err := NewDecryptor(tt.key).Decrypt(tt.data)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCiphertext)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, data, plain)
+		})
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writePEM := func(name, blockType string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0o600))
+		return path
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pkix, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	t.Run("PKCS #1 keys", func(t *testing.T) {
+		t.Parallel()
+
+		priv, err := LoadPrivateKey(writePEM("pkcs1.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)))
+		require.NoError(t, err)
+		assert.True(t, key.Equal(priv))
+
+		pub, err := LoadPublicKey(writePEM("pkcs1.pub", "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&key.PublicKey)))
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(pub))
+	})
+
+	t.Run("PKCS #8 and PKIX keys", func(t *testing.T) {
+		t.Parallel()
+
+		priv, err := LoadPrivateKey(writePEM("pkcs8.pem", "PRIVATE KEY", pkcs8))
+		require.NoError(t, err)
+		assert.True(t, key.Equal(priv))
+
+		pub, err := LoadPublicKey(writePEM("pkix.pub", "PUBLIC KEY", pkix))
+		require.NoError(t, err)
+		assert.True(t, key.PublicKey.Equal(pub))
+	})
+
+	t.Run("Not a PEM file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(dir, "garbage")
+		require.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+
+		_, err := LoadPrivateKey(path)
+		require.Error(t, err)
+	})
+}
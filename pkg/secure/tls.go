@@ -0,0 +1,82 @@
+package secure
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+
+	"github.com/yogenyslav/ya-metrics/pkg/errs"
+)
+
+// ServerTLSConfig creates a TLS configuration serving the certificate.
+//
+// Clients are required to present a certificate signed by the CA from clientCAFile unless it is empty.
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errs.Wrap(err, "load certificate")
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile != "" {
+		pool, err := loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, errs.Wrap(err, "load client CA")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// ClientTLSConfig creates a TLS configuration trusting the CA from caFile in addition to the system ones.
+//
+// The client certificate is presented to the server when certFile is not empty.
+func ClientTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if err := appendCerts(pool, caFile); err != nil {
+			return nil, errs.Wrap(err, "load CA")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errs.Wrap(err, "load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if err := appendCerts(pool, path); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+func appendCerts(pool *x509.CertPool, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errs.Wrap(err, "read certificates")
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return errors.New("no certificates in " + path)
+	}
+	return nil
+}
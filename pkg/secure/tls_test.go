@@ -0,0 +1,160 @@
+package secure
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certFile string
+	keyFile  string
+}
+
+// issueCert issues a certificate signed by the parent, nil parent makes a self-signed CA.
+func issueCert(t *testing.T, dir, name string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	tc := &testCert{
+		cert:     cert,
+		key:      key,
+		certFile: filepath.Join(dir, name+".crt"),
+		keyFile:  filepath.Join(dir, name+".key"),
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer})
+	require.NoError(t, os.WriteFile(tc.certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(tc.keyFile, keyPEM, 0o600))
+	return tc
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := issueCert(t, dir, "ca", nil)
+	server := issueCert(t, dir, "server", ca)
+	agent := issueCert(t, dir, "agent", ca)
+	otherCA := issueCert(t, dir, "other-ca", nil)
+	stranger := issueCert(t, dir, "stranger", otherCA)
+
+	tests := []struct {
+		name     string
+		clientCA string
+		caFile   string
+		client   *testCert
+		wantErr  bool
+	}{
+		{
+			name:   "Server trusted by custom CA",
+			caFile: ca.certFile,
+		},
+		{
+			name:    "Server not trusted without CA",
+			wantErr: true,
+		},
+		{
+			name:     "Agent authenticated by client certificate",
+			clientCA: ca.certFile,
+			caFile:   ca.certFile,
+			client:   agent,
+		},
+		{
+			name:     "Agent without client certificate",
+			clientCA: ca.certFile,
+			caFile:   ca.certFile,
+			wantErr:  true,
+		},
+		{
+			name:     "Agent certificate signed by other CA",
+			clientCA: ca.certFile,
+			caFile:   ca.certFile,
+			client:   stranger,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			serverCfg, err := ServerTLSConfig(server.certFile, server.keyFile, tt.clientCA)
+			require.NoError(t, err)
+
+			var subject string
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if len(r.TLS.VerifiedChains) > 0 {
+					subject = r.TLS.VerifiedChains[0][0].Subject.CommonName
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			srv.TLS = serverCfg
+			srv.StartTLS()
+			defer srv.Close()
+
+			var certFile, keyFile string
+			if tt.client != nil {
+				certFile, keyFile = tt.client.certFile, tt.client.keyFile
+			}
+			clientCfg, err := ClientTLSConfig(tt.caFile, certFile, keyFile)
+			require.NoError(t, err)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientCfg}}
+			resp, err := client.Get(srv.URL)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			if tt.client != nil {
+				assert.Equal(t, tt.client.cert.Subject.CommonName, subject)
+			}
+		})
+	}
+}
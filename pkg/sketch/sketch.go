@@ -0,0 +1,163 @@
+// Package sketch provides a mergeable quantile sketch with bounded relative error.
+//
+// Values are counted in logarithmically sized buckets, so that any quantile estimate
+// differs from the exact value by at most the configured relative accuracy.
+package sketch
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"slices"
+)
+
+// DefaultRelativeAccuracy is the relative error of quantile estimates used by default.
+const DefaultRelativeAccuracy = 0.01
+
+// minIndexableValue is the smallest absolute value counted in a bucket, smaller values are counted as zero.
+const minIndexableValue = 1e-9
+
+// ErrAccuracyMismatch is an error when sketches with different relative accuracy are merged.
+var ErrAccuracyMismatch = errors.New("sketches have different relative accuracy")
+
+// Sketch estimates quantiles of a stream of values.
+type Sketch struct {
+	RelativeAccuracy float64       `json:"alpha"`
+	Positive         map[int]int64 `json:"positive,omitempty"`
+	Negative         map[int]int64 `json:"negative,omitempty"`
+	Zero             int64         `json:"zero,omitempty"`
+	Count            int64         `json:"count"`
+	Sum              float64       `json:"sum"`
+	Min              float64       `json:"min"`
+	Max              float64       `json:"max"`
+}
+
+// New creates an empty Sketch with the given relative accuracy in (0, 1).
+func New(relativeAccuracy float64) *Sketch {
+	return &Sketch{RelativeAccuracy: relativeAccuracy}
+}
+
+// Add records a value.
+func (s *Sketch) Add(v float64) {
+	switch {
+	case v > minIndexableValue:
+		if s.Positive == nil {
+			s.Positive = make(map[int]int64)
+		}
+		s.Positive[s.index(v)]++
+	case v < -minIndexableValue:
+		if s.Negative == nil {
+			s.Negative = make(map[int]int64)
+		}
+		s.Negative[s.index(-v)]++
+	default:
+		s.Zero++
+	}
+
+	if s.Count == 0 || v < s.Min {
+		s.Min = v
+	}
+	if s.Count == 0 || v > s.Max {
+		s.Max = v
+	}
+	s.Count++
+	s.Sum += v
+}
+
+// Merge adds all values recorded by other sketch.
+func (s *Sketch) Merge(other *Sketch) error {
+	if other.Count == 0 {
+		return nil
+	}
+	if s.RelativeAccuracy != other.RelativeAccuracy {
+		return ErrAccuracyMismatch
+	}
+
+	s.Positive = mergeBuckets(s.Positive, other.Positive)
+	s.Negative = mergeBuckets(s.Negative, other.Negative)
+	s.Zero += other.Zero
+
+	if s.Count == 0 || other.Min < s.Min {
+		s.Min = other.Min
+	}
+	if s.Count == 0 || other.Max > s.Max {
+		s.Max = other.Max
+	}
+	s.Count += other.Count
+	s.Sum += other.Sum
+
+	return nil
+}
+
+// Quantile returns the estimate of the q-quantile, q must be in [0, 1].
+//
+// NaN is returned for an empty sketch or q out of range.
+func (s *Sketch) Quantile(q float64) float64 {
+	if s.Count == 0 || q < 0 || q > 1 {
+		return math.NaN()
+	}
+
+	rank := q * float64(s.Count-1)
+
+	var cumulative float64
+	for _, idx := range slices.Backward(slices.Sorted(maps.Keys(s.Negative))) {
+		cumulative += float64(s.Negative[idx])
+		if cumulative > rank {
+			return s.clamp(-s.value(idx))
+		}
+	}
+
+	cumulative += float64(s.Zero)
+	if cumulative > rank {
+		return s.clamp(0)
+	}
+
+	for _, idx := range slices.Sorted(maps.Keys(s.Positive)) {
+		cumulative += float64(s.Positive[idx])
+		if cumulative > rank {
+			return s.clamp(s.value(idx))
+		}
+	}
+
+	return s.Max
+}
+
+// Clone returns a deep copy of the sketch.
+func (s *Sketch) Clone() *Sketch {
+	c := *s
+	c.Positive = maps.Clone(s.Positive)
+	c.Negative = maps.Clone(s.Negative)
+	return &c
+}
+
+func (s *Sketch) gamma() float64 {
+	return (1 + s.RelativeAccuracy) / (1 - s.RelativeAccuracy)
+}
+
+// index returns the bucket of a positive value, bucket i covers (gamma^(i-1), gamma^i].
+func (s *Sketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / math.Log(s.gamma())))
+}
+
+// value returns the point of the bucket with equal relative distance to both of its bounds.
+func (s *Sketch) value(idx int) float64 {
+	g := s.gamma()
+	return 2 * math.Pow(g, float64(idx)) / (g + 1)
+}
+
+func (s *Sketch) clamp(v float64) float64 {
+	return max(s.Min, min(s.Max, v))
+}
+
+func mergeBuckets(dst, src map[int]int64) map[int]int64 {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[int]int64, len(src))
+	}
+	for idx, count := range src {
+		dst[idx] += count
+	}
+	return dst
+}
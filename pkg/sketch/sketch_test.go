@@ -0,0 +1,149 @@
+package sketch_test
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yogenyslav/ya-metrics/pkg/sketch"
+)
+
+func exactQuantile(sorted []float64, q float64) float64 {
+	return sorted[int(q*float64(len(sorted)-1))]
+}
+
+func TestSketch_Quantile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		values []float64
+	}{
+		{
+			name: "Uniform positive values",
+			values: func() []float64 {
+				values := make([]float64, 0, 10000)
+				for i := 1; i <= 10000; i++ {
+					values = append(values, float64(i))
+				}
+				return values
+			}(),
+		},
+		{
+			name: "Exponentially spread values",
+			values: func() []float64 {
+				values := make([]float64, 0, 1000)
+				for i := range 1000 {
+					values = append(values, math.Pow(1.02, float64(i))*1e-3)
+				}
+				return values
+			}(),
+		},
+		{
+			name:   "Negative, zero and positive values",
+			values: []float64{-100, -10, -1, 0, 0, 1, 10, 100, 1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := sketch.New(sketch.DefaultRelativeAccuracy)
+			for _, v := range tt.values {
+				s.Add(v)
+			}
+
+			sorted := slices.Sorted(slices.Values(tt.values))
+			for _, q := range []float64{0, 0.25, 0.5, 0.9, 0.99, 1} {
+				want := exactQuantile(sorted, q)
+				got := s.Quantile(q)
+				assert.InDelta(t, want, got, math.Abs(want)*sketch.DefaultRelativeAccuracy+1e-12, "q=%v", q)
+			}
+
+			assert.Equal(t, int64(len(tt.values)), s.Count)
+			assert.Equal(t, sorted[0], s.Min)
+			assert.Equal(t, sorted[len(sorted)-1], s.Max)
+		})
+	}
+}
+
+func TestSketch_Quantile_empty(t *testing.T) {
+	t.Parallel()
+
+	s := sketch.New(sketch.DefaultRelativeAccuracy)
+	assert.True(t, math.IsNaN(s.Quantile(0.5)))
+
+	s.Add(1)
+	assert.True(t, math.IsNaN(s.Quantile(1.5)))
+}
+
+func TestSketch_Merge(t *testing.T) {
+	t.Parallel()
+
+	all := sketch.New(sketch.DefaultRelativeAccuracy)
+	left := sketch.New(sketch.DefaultRelativeAccuracy)
+	right := sketch.New(sketch.DefaultRelativeAccuracy)
+	for i := -50; i <= 200; i++ {
+		v := float64(i) / 3
+		all.Add(v)
+		if i%2 == 0 {
+			left.Add(v)
+		} else {
+			right.Add(v)
+		}
+	}
+
+	require.NoError(t, left.Merge(right))
+	assert.Equal(t, all.Count, left.Count)
+	assert.Equal(t, all.Min, left.Min)
+	assert.Equal(t, all.Max, left.Max)
+	assert.InDelta(t, all.Sum, left.Sum, 1e-9)
+	assert.Equal(t, all.Positive, left.Positive)
+	assert.Equal(t, all.Negative, left.Negative)
+	assert.Equal(t, all.Zero, left.Zero)
+
+	empty := sketch.New(sketch.DefaultRelativeAccuracy)
+	require.NoError(t, empty.Merge(all))
+	assert.Equal(t, all.Quantile(0.5), empty.Quantile(0.5))
+
+	require.ErrorIs(t, all.Merge(func() *sketch.Sketch {
+		s := sketch.New(0.05)
+		s.Add(1)
+		return s
+	}()), sketch.ErrAccuracyMismatch)
+}
+
+func TestSketch_Clone(t *testing.T) {
+	t.Parallel()
+
+	s := sketch.New(sketch.DefaultRelativeAccuracy)
+	s.Add(1)
+	s.Add(-1)
+
+	c := s.Clone()
+	c.Add(1)
+
+	assert.Equal(t, int64(2), s.Count)
+	assert.Equal(t, int64(3), c.Count)
+	assert.NotEqual(t, s.Positive, c.Positive)
+}
+
+func TestSketch_JSON(t *testing.T) {
+	t.Parallel()
+
+	s := sketch.New(sketch.DefaultRelativeAccuracy)
+	for _, v := range []float64{-3, 0, 0.5, 2, 2, 40} {
+		s.Add(v)
+	}
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	var restored sketch.Sketch
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.Equal(t, s, &restored)
+}
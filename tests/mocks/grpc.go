@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yogenyslav/ya-metrics/internal/pb"
+	"google.golang.org/grpc"
+)
+
+// GRPCClient is a mock gRPC metrics client.
+type GRPCClient struct {
+	mock.Mock
+}
+
+// UpdateBatch performs a mock batch update.
+func (c *GRPCClient) UpdateBatch(
+	ctx context.Context,
+	in *pb.UpdateBatchRequest,
+	_ ...grpc.CallOption,
+) (*pb.UpdateBatchResponse, error) {
+	args := c.Called(ctx, in)
+	return args.Get(0).(*pb.UpdateBatchResponse), args.Error(1)
+}
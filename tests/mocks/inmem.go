@@ -2,6 +2,7 @@ package mocks
 
 import (
 	context "context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/yogenyslav/ya-metrics/internal/model"
@@ -17,8 +18,12 @@ func (m *MockMetricRepo[T]) GetMetrics(ctx context.Context) ([]*model.MetricsDto
 	return args.Get(0).([]*model.MetricsDto), args.Error(1)
 }
 
-func (m *MockMetricRepo[T]) Get(ctx context.Context, metricName, metricType string) (*model.Metrics[T], error) {
-	args := m.Called(ctx, metricName, metricType)
+func (m *MockMetricRepo[T]) Get(
+	ctx context.Context,
+	metricName, metricType string,
+	labels model.Labels,
+) (*model.Metrics[T], error) {
+	args := m.Called(ctx, metricName, metricType, labels)
 	m.ExpectedCalls = m.ExpectedCalls[1:]
 	return args.Get(0).(*model.Metrics[T]), args.Error(1)
 }
@@ -29,6 +34,40 @@ func (m *MockMetricRepo[T]) List(ctx context.Context) ([]model.Metrics[T], error
 	return args.Get(0).([]model.Metrics[T]), args.Error(1)
 }
 
+func (m *MockMetricRepo[T]) Append(ctx context.Context, s *model.Sample[T]) error {
+	args := m.Called(ctx, s)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Error(0)
+}
+
+func (m *MockMetricRepo[T]) Range(
+	ctx context.Context,
+	metricName, metricType string,
+	labels model.Labels,
+	from, to time.Time,
+) ([]model.Sample[T], error) {
+	args := m.Called(ctx, metricName, metricType, labels, from, to)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).([]model.Sample[T]), args.Error(1)
+}
+
+func (m *MockMetricRepo[T]) LatestBefore(
+	ctx context.Context,
+	metricName, metricType string,
+	labels model.Labels,
+	before time.Time,
+) (*model.Sample[T], error) {
+	args := m.Called(ctx, metricName, metricType, labels, before)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).(*model.Sample[T]), args.Error(1)
+}
+
+func (m *MockMetricRepo[T]) DeleteSamplesBefore(ctx context.Context, metricType string, before time.Time) error {
+	args := m.Called(ctx, metricType, before)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Error(0)
+}
+
 type MockGaugeRepo struct {
 	MockMetricRepo[float64]
 }
@@ -49,10 +88,10 @@ type MockCounterRepo struct {
 	MockMetricRepo[int64]
 }
 
-func (m *MockCounterRepo) Update(ctx context.Context, metric *model.Metrics[int64]) error {
+func (m *MockCounterRepo) Update(ctx context.Context, metric *model.Metrics[int64]) (int64, error) {
 	args := m.Called(ctx, metric)
 	m.ExpectedCalls = m.ExpectedCalls[1:]
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 func (m *MockCounterRepo) UpdateBatch(ctx context.Context, metrics []*model.Metrics[int64]) error {
@@ -60,3 +99,95 @@ func (m *MockCounterRepo) UpdateBatch(ctx context.Context, metrics []*model.Metr
 	m.ExpectedCalls = m.ExpectedCalls[1:]
 	return args.Error(0)
 }
+
+type MockHistogramRepo struct {
+	mock.Mock
+}
+
+func (m *MockHistogramRepo) GetMetrics(ctx context.Context) ([]*model.MetricsDto, error) {
+	args := m.Called(ctx)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).([]*model.MetricsDto), args.Error(1)
+}
+
+func (m *MockHistogramRepo) Get(
+	ctx context.Context,
+	metricName string,
+	labels model.Labels,
+) (*model.HistogramMetric, error) {
+	args := m.Called(ctx, metricName, labels)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).(*model.HistogramMetric), args.Error(1)
+}
+
+func (m *MockHistogramRepo) List(ctx context.Context) ([]model.HistogramMetric, error) {
+	args := m.Called(ctx)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).([]model.HistogramMetric), args.Error(1)
+}
+
+func (m *MockHistogramRepo) Merge(ctx context.Context, h *model.HistogramMetric) error {
+	args := m.Called(ctx, h)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Error(0)
+}
+
+type MockSummaryRepo struct {
+	mock.Mock
+}
+
+func (m *MockSummaryRepo) GetMetrics(ctx context.Context) ([]*model.MetricsDto, error) {
+	args := m.Called(ctx)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).([]*model.MetricsDto), args.Error(1)
+}
+
+func (m *MockSummaryRepo) Get(
+	ctx context.Context,
+	metricName string,
+	labels model.Labels,
+) (*model.SummaryMetric, error) {
+	args := m.Called(ctx, metricName, labels)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).(*model.SummaryMetric), args.Error(1)
+}
+
+func (m *MockSummaryRepo) List(ctx context.Context) ([]model.SummaryMetric, error) {
+	args := m.Called(ctx)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).([]model.SummaryMetric), args.Error(1)
+}
+
+func (m *MockSummaryRepo) Merge(ctx context.Context, s *model.SummaryMetric) error {
+	args := m.Called(ctx, s)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Error(0)
+}
+
+type MockRollupRepo struct {
+	mock.Mock
+}
+
+func (m *MockRollupRepo) Save(ctx context.Context, rollups ...model.Rollup) error {
+	args := m.Called(ctx, rollups)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Error(0)
+}
+
+func (m *MockRollupRepo) Range(
+	ctx context.Context,
+	metricID, metricType string,
+	labels model.Labels,
+	resolution int64,
+	from, to time.Time,
+) ([]model.Rollup, error) {
+	args := m.Called(ctx, metricID, metricType, labels, resolution, from, to)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).([]model.Rollup), args.Error(1)
+}
+
+func (m *MockRollupRepo) DeleteBefore(ctx context.Context, resolution int64, before time.Time) error {
+	args := m.Called(ctx, resolution, before)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Error(0)
+}
@@ -23,8 +23,12 @@ func (m *MockMetricService) UpdateMetricsBatch(ctx context.Context, metrics []*m
 	return args.Error(0)
 }
 
-func (m *MockMetricService) GetMetric(ctx context.Context, metricType, metricID string) (*model.MetricsDto, error) {
-	args := m.Called(ctx, metricType, metricID)
+func (m *MockMetricService) GetMetric(
+	ctx context.Context,
+	metricType, metricID string,
+	labels model.Labels,
+) (*model.MetricsDto, error) {
+	args := m.Called(ctx, metricType, metricID, labels)
 	m.ExpectedCalls = m.ExpectedCalls[1:]
 	return args.Get(0).(*model.MetricsDto), args.Error(1)
 }
@@ -34,3 +38,15 @@ func (m *MockMetricService) ListMetrics(ctx context.Context) ([]*model.MetricsDt
 	m.ExpectedCalls = m.ExpectedCalls[1:]
 	return args.Get(0).([]*model.MetricsDto), args.Error(1)
 }
+
+func (m *MockMetricService) ListMetricGroups(ctx context.Context) ([]*model.MetricGroup, error) {
+	args := m.Called(ctx)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).([]*model.MetricGroup), args.Error(1)
+}
+
+func (m *MockMetricService) QueryRange(ctx context.Context, q *model.RangeQuery) (*model.Series, error) {
+	args := m.Called(ctx, q)
+	m.ExpectedCalls = m.ExpectedCalls[1:]
+	return args.Get(0).(*model.Series), args.Error(1)
+}